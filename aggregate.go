@@ -1,6 +1,8 @@
 package easymongo
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -27,15 +29,20 @@ func (c *Collection) Aggregate(pipeline interface{}) *AggregationQuery {
 // mongo.Cursor that can be worked with directly. This is typically useful
 // when returning large numbers of results. If you just need to get at the documents without
 // iterating, call .One() or .All()
-func (p *AggregationQuery) Cursor() (*mongo.Cursor, error) {
-	coll := p.collection.mongoColl
-	ctx, cancelFunc := p.getContext()
-	defer cancelFunc()
-	opts := p.aggregateOptions()
-	cursor, err := coll.Aggregate(ctx, p.filter, opts)
+func (p *AggregationQuery) Cursor() (cursor *mongo.Cursor, err error) {
+	err = p.run(opAggregateCursor, func(ctx context.Context) error {
+		cursor, err = p.cursor(ctx)
+		return err
+	})
 	return cursor, p.collection.handleErr(err)
 }
 
+// cursor runs the aggregation using the provided context
+func (p *AggregationQuery) cursor(ctx context.Context) (*mongo.Cursor, error) {
+	opts := p.aggregateOptions()
+	return p.collection.mongoCollection().Aggregate(ctx, p.filter, opts)
+}
+
 func (p *AggregationQuery) aggregateOptions() *options.AggregateOptions {
 	opts := &options.AggregateOptions{
 		AllowDiskUse:             p.allowDiskUse,
@@ -53,27 +60,33 @@ func (p *AggregationQuery) aggregateOptions() *options.AggregateOptions {
 
 // All executes the aggregation and returns the resultant output to the provided result object.
 func (p *AggregationQuery) All(result interface{}) error {
-	cursor, err := p.Cursor()
-	if err != nil {
-		return err
-	}
-	ctx, cancelFunc := p.getContext()
-	defer cancelFunc()
-	if err = cursor.All(ctx, result); err != nil {
-		return err
-	}
+	return p.run(opAggregateAll, func(ctx context.Context) error {
+		cursor, err := p.cursor(ctx)
+		if err = p.collection.handleErr(err); err != nil {
+			return err
+		}
+		if err = cursor.All(ctx, result); err != nil {
+			return err
+		}
 
-	return p.collection.handleErr(err)
+		return p.collection.handleErr(err)
+	})
 }
 
 // One executes the aggregation and returns the first result to the provided result object.
 func (p *AggregationQuery) One(result interface{}) error {
-	cursor, err := p.Cursor()
-	if err != nil {
+	return p.run(opAggregateOne, func(ctx context.Context) error {
+		return p.one(ctx, result)
+	})
+}
+
+// one runs the aggregation using the provided context and decodes the first result
+func (p *AggregationQuery) one(ctx context.Context, result interface{}) error {
+	cursor, err := p.cursor(ctx)
+	if err = p.collection.handleErr(err); err != nil {
 		return err
 	}
-	ctx, cancelFunc := p.getContext()
-	defer cancelFunc()
+	defer cursor.Close(ctx)
 	if found := cursor.Next(ctx); !found {
 		// Move the cursor
 		err = ErrNoDocuments
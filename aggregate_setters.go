@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // BatchSize overrides the batch size for how documents are returned.
@@ -76,3 +79,35 @@ func (p *AggregationQuery) WithContext(ctx context.Context) *AggregationQuery {
 	p.Query.setContext(&ctx)
 	return p
 }
+
+// Retry overrides the connection's RetryPolicy for this query. Use RetryPolicy{MaxAttempts: 1} to disable retrying.
+func (p *AggregationQuery) Retry(policy RetryPolicy) *AggregationQuery {
+	p.Query.setRetry(policy)
+	return p
+}
+
+// ReadPreference overrides the read preference for this query only.
+// e.g. ReadPreference(readpref.SecondaryPreferred())
+func (p *AggregationQuery) ReadPreference(rp *readpref.ReadPref) *AggregationQuery {
+	p.Query.setReadPreference(rp)
+	return p
+}
+
+// ReadConcern overrides the read concern for this query only.
+func (p *AggregationQuery) ReadConcern(rc *readconcern.ReadConcern) *AggregationQuery {
+	p.Query.setReadConcern(rc)
+	return p
+}
+
+// WriteConcern overrides the write concern for this query only.
+func (p *AggregationQuery) WriteConcern(wc *writeconcern.WriteConcern) *AggregationQuery {
+	p.Query.setWriteConcern(wc)
+	return p
+}
+
+// Flags overrides the read concern, read preference and write concern set in flags for this query only.
+// e.g. Flags(ReadPreferenceSecondaryPreferred | ReadConcernMajority)
+func (p *AggregationQuery) Flags(flags ConnectionFlag) *AggregationQuery {
+	p.Query.setFlags(flags)
+	return p
+}
@@ -98,6 +98,6 @@ func TestAggregate(t *testing.T) {
 		is.Equal(3, result.Total, "the count appears to be incorrect from the aggregation result")
 		// Set a timeout of 0 so we trigger an error
 		err = coll.Aggregate(pipe).Timeout(0).One(&result)
-		is.Equal(easymongo.ErrTimeoutOccurred, err, "A timeout was expected")
+		is.ErrorIs(err, easymongo.ErrTimeoutOccurred, "A timeout was expected")
 	})
 }
@@ -0,0 +1,295 @@
+package easymongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// CircuitState is the state of a connection's circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed means operations are running normally
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen means operations fail immediately with ErrCircuitOpen
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen means the cluster is being probed with a ping to decide whether to close the circuit
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// circuitBuckets is the number of buckets the failure rate window is divided into
+const circuitBuckets = 10
+
+// CircuitBreakerPolicy controls when the circuit breaker opens. Only failures which suggest the cluster is
+// unavailable are counted (e.g. timeouts, network errors, server selection failures and NotWritablePrimary).
+// Errors such as mongo.ErrNoDocuments, a duplicate key or a WriteConflict count as successes, as the cluster responded.
+type CircuitBreakerPolicy struct {
+	// ConsecutiveFailures opens the circuit after this many operations fail in a row. 0 disables the check.
+	ConsecutiveFailures int
+	// FailureRate opens the circuit once this fraction (0-1) of the operations within Window fail. 0 disables the check.
+	FailureRate float64
+	// MinRequests is the number of operations which must have run within Window before FailureRate applies
+	MinRequests int
+	// Window is the period over which FailureRate is measured
+	Window time.Duration
+	// OpenTimeout is how long the circuit stays open before the cluster is probed with a ping.
+	// Operations fail with ErrCircuitOpen while the circuit is open or the probe is running.
+	OpenTimeout time.Duration
+	// ProbeTimeout bounds how long the ping probing the cluster may take (1 second if it is not set).
+	// The probe is run by the first operation after OpenTimeout, so it is also bounded by that operation's context.
+	ProbeTimeout time.Duration
+}
+
+// defaultProbeTimeout bounds the probe when the policy's ProbeTimeout is not set
+const defaultProbeTimeout = time.Second
+
+// DefaultCircuitBreakerPolicy opens after 5 consecutive failures or once half of at least 20 operations
+// fail within 10 seconds, and probes the cluster every 5 seconds while open.
+var DefaultCircuitBreakerPolicy = CircuitBreakerPolicy{
+	ConsecutiveFailures: 5,
+	FailureRate:         0.5,
+	MinRequests:         20,
+	Window:              10 * time.Second,
+	OpenTimeout:         5 * time.Second,
+	ProbeTimeout:        time.Second,
+}
+
+// CircuitChangeFunc is called whenever the state of a connection's circuit breaker changes.
+type CircuitChangeFunc func(previous, current CircuitState)
+
+// circuitBucket counts the operations which completed during one slice of the failure rate window
+type circuitBucket struct {
+	start    time.Time
+	requests int
+	failures int
+}
+
+// circuitBreaker fails operations fast while the cluster is unavailable
+type circuitBreaker struct {
+	lock   sync.Mutex
+	policy *CircuitBreakerPolicy
+	state  CircuitState
+	// consecutive is the number of operations which have failed in a row
+	consecutive int
+	buckets     [circuitBuckets]circuitBucket
+	openedAt    time.Time
+	callbacks   []CircuitChangeFunc
+}
+
+// CircuitBreaker enables a circuit breaker which fails operations immediately with ErrCircuitOpen while
+// the cluster is unavailable, rather than letting each of them wait for its timeout.
+// The state of the circuit is available using Connection.Health().
+// e.g. ConnectWith(mongoURI).CircuitBreaker(DefaultCircuitBreakerPolicy).OnCircuitChange(alertFunc).Connect()
+func (cb *ConnectionBuilder) CircuitBreaker(policy CircuitBreakerPolicy) *ConnectionBuilder {
+	cb.connection.breaker.policy = &policy
+	return cb
+}
+
+// OnCircuitChange registers a callback which is called whenever the state of the circuit breaker changes.
+// Callbacks are called from the goroutine running the operation, so they should not block.
+func (cb *ConnectionBuilder) OnCircuitChange(fn CircuitChangeFunc) *ConnectionBuilder {
+	cb.connection.breaker.callbacks = append(cb.connection.breaker.callbacks, fn)
+	return cb
+}
+
+// circuitState returns the state of the circuit breaker ("" if it is not enabled)
+func (br *circuitBreaker) circuitState() CircuitState {
+	if br.policy == nil {
+		return ""
+	}
+	br.lock.Lock()
+	defer br.lock.Unlock()
+	if br.state == "" {
+		return CircuitClosed
+	}
+	return br.state
+}
+
+// allow returns ErrCircuitOpen if the operation should not run. Once OpenTimeout has passed, the first caller
+// pings the cluster (bounded by ctx and ProbeTimeout), closing the circuit if the ping succeeds and re-opening it otherwise.
+// Should ctx be done before the ping succeeds, the caller is refused but the circuit is not re-opened for another
+// OpenTimeout - the next caller probes the cluster instead.
+func (br *circuitBreaker) allow(ctx context.Context, conn *Connection) error {
+	if br.policy == nil || conn.IsClosed() {
+		return nil
+	}
+	br.lock.Lock()
+	switch br.state {
+	case CircuitOpen:
+		if time.Since(br.openedAt) < br.policy.OpenTimeout {
+			br.lock.Unlock()
+			return ErrCircuitOpen
+		}
+		notify := br.setState(conn, CircuitHalfOpen)
+		br.lock.Unlock()
+		notify()
+	case CircuitHalfOpen:
+		br.lock.Unlock()
+		return ErrCircuitOpen
+	default:
+		br.lock.Unlock()
+		return nil
+	}
+
+	probeTimeout := br.policy.ProbeTimeout
+	if probeTimeout <= 0 {
+		probeTimeout = defaultProbeTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	err := conn.ping(probeCtx)
+	cancel()
+	br.lock.Lock()
+	var notify func()
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up, which says nothing about the cluster. openedAt is kept, so the next caller probes.
+		notify = br.setState(conn, CircuitOpen)
+		err = ErrCircuitOpen
+	} else if err != nil {
+		notify = br.trip(conn, err)
+		err = ErrCircuitOpen
+	} else {
+		br.reset()
+		notify = br.setState(conn, CircuitClosed)
+	}
+	br.lock.Unlock()
+	notify()
+	return err
+}
+
+// record counts the result of an operation, opening the circuit should the policy's thresholds be exceeded
+func (br *circuitBreaker) record(conn *Connection, err error) {
+	if br.policy == nil || errors.Is(err, ErrConnectionClosed) || errors.Is(err, context.Canceled) {
+		return
+	}
+	failed := isCircuitFailure(err)
+	br.lock.Lock()
+	notify := func() {}
+	defer func() {
+		br.lock.Unlock()
+		notify()
+	}()
+	if br.state != "" && br.state != CircuitClosed {
+		// Operations which started before the circuit opened don't count
+		return
+	}
+
+	now := time.Now()
+	bucketWidth := br.policy.Window / circuitBuckets
+	if bucketWidth <= 0 {
+		bucketWidth = 1
+	}
+	bucketStart := now.Truncate(bucketWidth)
+	bucket := &br.buckets[(bucketStart.UnixNano()/int64(bucketWidth))%circuitBuckets]
+	if !bucket.start.Equal(bucketStart) {
+		*bucket = circuitBucket{start: bucketStart}
+	}
+	bucket.requests++
+	if !failed {
+		br.consecutive = 0
+		return
+	}
+	bucket.failures++
+	br.consecutive++
+
+	if br.policy.ConsecutiveFailures > 0 && br.consecutive >= br.policy.ConsecutiveFailures {
+		notify = br.trip(conn, err)
+		return
+	}
+	if br.policy.FailureRate > 0 {
+		var requests, failures int
+		for _, b := range br.buckets {
+			if now.Sub(b.start) < br.policy.Window {
+				requests += b.requests
+				failures += b.failures
+			}
+		}
+		if requests >= br.policy.MinRequests && float64(failures) >= br.policy.FailureRate*float64(requests) {
+			notify = br.trip(conn, err)
+		}
+	}
+}
+
+// trip opens the circuit. lock must be held. The returned function calls the callbacks and must be
+// called once lock is released.
+func (br *circuitBreaker) trip(conn *Connection, err error) (notify func()) {
+	br.reset()
+	br.openedAt = time.Now()
+	if br.state != CircuitOpen {
+		conn.logger().WithField("error", err).Warnf("Mongo circuit breaker opened after: %v", err)
+	}
+	return br.setState(conn, CircuitOpen)
+}
+
+// reset clears the failure counts. lock must be held.
+func (br *circuitBreaker) reset() {
+	br.consecutive = 0
+	br.buckets = [circuitBuckets]circuitBucket{}
+}
+
+// setState changes the state of the circuit. lock must be held. The returned function calls the
+// callbacks and must be called once lock is released, so callbacks may call Connection.Health().
+func (br *circuitBreaker) setState(conn *Connection, state CircuitState) (notify func()) {
+	previous := br.state
+	if previous == "" {
+		previous = CircuitClosed
+	}
+	br.state = state
+	if previous == state {
+		return func() {}
+	}
+	if state == CircuitClosed {
+		conn.logger().Infof("Mongo circuit breaker closed")
+	}
+	callbacks := br.callbacks
+	return func() {
+		for _, fn := range callbacks {
+			fn(previous, state)
+		}
+	}
+}
+
+// outageCodes are returned by a cluster which is (perhaps briefly) unable to serve operations, e.g. during
+// an election or a shutdown. Contention (e.g. WriteConflict) and per-operation limits (e.g. ExceededTimeLimit)
+// are left out, as the cluster is responding.
+var outageCodes = []int{
+	codeHostUnreachable,
+	codeHostNotFound,
+	codeNetworkTimeout,
+	codeShutdownInProgress,
+	codePrimarySteppedDown,
+	codeSocketException,
+	codeNotWritablePrimary,
+	codeInterruptedAtShutdown,
+	codeInterruptedDueToReplStateChange,
+	codeNotPrimaryNoSecondaryOk,
+	codeNotPrimaryOrSecondary,
+}
+
+// isCircuitFailure returns true if err suggests the cluster is unavailable: network errors, server selection
+// failures, not-primary and shutdown codes, and timeouts
+func isCircuitFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrTimeoutOccurred) || errors.Is(err, context.DeadlineExceeded) || mongo.IsNetworkError(err) {
+		return true
+	}
+	var selectionErr topology.ServerSelectionError
+	if errors.As(err, &selectionErr) {
+		return true
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		for _, code := range outageCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package easymongo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	is := assert.New(t)
+	var lock sync.Mutex
+	var changes []easymongo.CircuitState
+	// Nothing is listening on this port, so every operation fails
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		DefaultQueryTimeout(50 * time.Millisecond).
+		CircuitBreaker(easymongo.CircuitBreakerPolicy{
+			ConsecutiveFailures: 2,
+			OpenTimeout:         100 * time.Millisecond,
+			ProbeTimeout:        100 * time.Millisecond,
+		}).
+		OnCircuitChange(func(previous, current easymongo.CircuitState) {
+			lock.Lock()
+			defer lock.Unlock()
+			changes = append(changes, current)
+		}).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+	coll := tmpConn.Database("batman_archive").C("enemies")
+	is.Equal(easymongo.CircuitClosed, tmpConn.Health().Circuit)
+
+	var e enemy
+	for i := 0; i < 2; i++ {
+		err = coll.Find(bson.M{}).One(&e)
+		is.Error(err)
+		is.False(errors.Is(err, easymongo.ErrCircuitOpen), "The circuit should only open after the failures")
+	}
+	is.Equal(easymongo.CircuitOpen, tmpConn.Health().Circuit)
+
+	start := time.Now()
+	_, err = coll.Insert().One(&enemy{Name: "The Joker"})
+	is.True(errors.Is(err, easymongo.ErrCircuitOpen))
+	is.Less(int64(time.Since(start)), int64(10*time.Millisecond), "An open circuit should fail fast")
+
+	// Once OpenTimeout passes, the next operation probes the cluster with a ping, which fails.
+	// The probe is bounded by ProbeTimeout, even though the operation's own context allows far longer.
+	time.Sleep(150 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start = time.Now()
+	err = coll.Find(bson.M{}).WithContext(ctx).One(&e)
+	is.True(errors.Is(err, easymongo.ErrCircuitOpen))
+	is.Less(int64(time.Since(start)), int64(time.Second), "The probe should not outlive ProbeTimeout")
+	is.Equal(easymongo.CircuitOpen, tmpConn.Health().Circuit)
+
+	// The probe is also bounded by the operation's context
+	time.Sleep(150 * time.Millisecond)
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer shortCancel()
+	start = time.Now()
+	err = coll.Find(bson.M{}).WithContext(shortCtx).One(&e)
+	is.True(errors.Is(err, easymongo.ErrCircuitOpen))
+	is.Less(int64(time.Since(start)), int64(90*time.Millisecond), "The probe should not outlive the operation's context")
+	is.Equal(easymongo.CircuitOpen, tmpConn.Health().Circuit)
+
+	// The caller giving up says nothing about the cluster, so the next operation probes it right away
+	err = coll.Find(bson.M{}).WithContext(ctx).One(&e)
+	is.True(errors.Is(err, easymongo.ErrCircuitOpen))
+
+	lock.Lock()
+	defer lock.Unlock()
+	is.Equal([]easymongo.CircuitState{easymongo.CircuitOpen, easymongo.CircuitHalfOpen, easymongo.CircuitOpen,
+		easymongo.CircuitHalfOpen, easymongo.CircuitOpen, easymongo.CircuitHalfOpen, easymongo.CircuitOpen}, changes)
+}
+
+func TestCircuitBreakerFailures(t *testing.T) {
+	is := assert.New(t)
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		CircuitBreaker(easymongo.CircuitBreakerPolicy{ConsecutiveFailures: 2, OpenTimeout: time.Minute}).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+
+	// Contention and per-operation time limits mean the cluster is responding
+	for _, err := range []error{
+		mongo.CommandError{Code: 112, Name: "WriteConflict", Labels: []string{"TransientTransactionError"}},
+		mongo.CommandError{Code: 112, Name: "WriteConflict"},
+		mongo.CommandError{Code: 251, Name: "NoSuchTransaction", Labels: []string{"TransientTransactionError"}},
+		mongo.CommandError{Code: 262, Name: "ExceededTimeLimit"},
+		mongo.ErrNoDocuments,
+	} {
+		tmpConn.RecordCircuit(err)
+		tmpConn.RecordCircuit(err)
+		is.Equal(easymongo.CircuitClosed, tmpConn.Health().Circuit, "%v should not open the circuit", err)
+	}
+
+	tmpConn.RecordCircuit(mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"})
+	tmpConn.RecordCircuit(mongo.CommandError{Code: 91, Name: "ShutdownInProgress"})
+	is.Equal(easymongo.CircuitOpen, tmpConn.Health().Circuit, "Not primary and shutdown errors should open the circuit")
+}
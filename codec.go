@@ -0,0 +1,215 @@
+package easymongo
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// TypeCodec registers a codec for a type in the registry a connection encodes and decodes values with.
+type TypeCodec struct {
+	// Type is the type the codec handles
+	Type reflect.Type
+	// Codec encodes and decodes values of Type
+	Codec bsoncodec.ValueCodec
+	// Hook registers the codec for every type which implements Type (which must be an interface),
+	// rather than for Type itself
+	Hook bool
+}
+
+// register adds the codec to rb
+func (tc TypeCodec) register(rb *bsoncodec.RegistryBuilder) {
+	if tc.Hook {
+		rb.RegisterHookEncoder(tc.Type, tc.Codec)
+		rb.RegisterHookDecoder(tc.Type, tc.Codec)
+		return
+	}
+	rb.RegisterTypeEncoder(tc.Type, tc.Codec)
+	rb.RegisterTypeDecoder(tc.Type, tc.Codec)
+}
+
+// RegisterTypeCodec uses codec to encode and decode values of type t.
+// e.g. ConnectWith(mongoURI).RegisterTypeCodec(reflect.TypeOf(Money{}), moneyCodec).Connect()
+func (cb *ConnectionBuilder) RegisterTypeCodec(t reflect.Type, codec bsoncodec.ValueCodec) *ConnectionBuilder {
+	return cb.Codecs(TypeCodec{Type: t, Codec: codec})
+}
+
+// RegisterHookCodec uses codec to encode and decode values of any type which implements the interface t.
+// e.g. ConnectWith(mongoURI).RegisterHookCodec(reflect.TypeOf((*Enum)(nil)).Elem(), enumCodec).Connect()
+func (cb *ConnectionBuilder) RegisterHookCodec(t reflect.Type, codec bsoncodec.ValueCodec) *ConnectionBuilder {
+	return cb.Codecs(TypeCodec{Type: t, Codec: codec, Hook: true})
+}
+
+// Codecs registers codecs, such as the built-in UUIDCodec, DurationCodec and IPCodec.
+// Codecs registered later take precedence over earlier codecs for the same type.
+// e.g. ConnectWith(mongoURI).Codecs(UUIDCodec(reflect.TypeOf(uuid.UUID{})), DurationCodec(), IPCodec()).Connect()
+func (cb *ConnectionBuilder) Codecs(codecs ...TypeCodec) *ConnectionBuilder {
+	cb.connection.mongoOptions.codecs = append(cb.connection.mongoOptions.codecs, codecs...)
+	return cb
+}
+
+// UUIDCodec stores uuidType (which must be a [16]byte array, such as github.com/google/uuid.UUID) as
+// binary subtype 4. Binary subtype 3 (legacy UUIDs) is also accepted when decoding.
+func UUIDCodec(uuidType reflect.Type) TypeCodec {
+	return TypeCodec{Type: uuidType, Codec: uuidCodec{}}
+}
+
+// DurationCodec stores time.Duration values as strings (e.g. "1h30m0s") rather than as nanoseconds.
+// Durations stored as numbers (the driver's default) can still be decoded.
+func DurationCodec() TypeCodec {
+	return TypeCodec{Type: reflect.TypeOf(time.Duration(0)), Codec: durationCodec{}}
+}
+
+// IPCodec stores net.IP values as strings (e.g. "10.0.0.1") rather than as binary.
+// IPs stored as binary (the driver's default) can still be decoded.
+func IPCodec() TypeCodec {
+	return TypeCodec{Type: reflect.TypeOf(net.IP{}), Codec: ipCodec{}}
+}
+
+// uuidCodec encodes [16]byte arrays as binary subtype 4
+type uuidCodec struct{}
+
+// isUUID returns true if val is a [16]byte array
+func isUUID(val reflect.Value) bool {
+	return val.IsValid() && val.Kind() == reflect.Array && val.Len() == 16 && val.Type().Elem().Kind() == reflect.Uint8
+}
+
+func (uuidCodec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !isUUID(val) {
+		return bsoncodec.ValueEncoderError{Name: "UUIDEncodeValue", Kinds: []reflect.Kind{reflect.Array}, Received: val}
+	}
+	b := make([]byte, 16)
+	reflect.Copy(reflect.ValueOf(b), val)
+	return vw.WriteBinaryWithSubtype(b, bsontype.BinaryUUID)
+}
+
+func (uuidCodec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !isUUID(val) || !val.CanSet() {
+		return bsoncodec.ValueDecoderError{Name: "UUIDDecodeValue", Kinds: []reflect.Kind{reflect.Array}, Received: val}
+	}
+	switch vr.Type() {
+	case bsontype.Null:
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadNull()
+	case bsontype.Binary:
+		b, subtype, err := vr.ReadBinary()
+		if err != nil {
+			return err
+		}
+		if subtype != bsontype.BinaryUUID && subtype != bsontype.BinaryUUIDOld {
+			return fmt.Errorf("cannot decode binary subtype %d into a UUID", subtype)
+		}
+		if len(b) != 16 {
+			return fmt.Errorf("cannot decode %d bytes into a UUID", len(b))
+		}
+		reflect.Copy(val, reflect.ValueOf(b))
+		return nil
+	}
+	return fmt.Errorf("cannot decode %v into a UUID", vr.Type())
+}
+
+// durationCodec encodes time.Duration values as strings
+type durationCodec struct{}
+
+var tDuration = reflect.TypeOf(time.Duration(0))
+
+func (durationCodec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tDuration {
+		return bsoncodec.ValueEncoderError{Name: "DurationEncodeValue", Types: []reflect.Type{tDuration}, Received: val}
+	}
+	return vw.WriteString(time.Duration(val.Int()).String())
+}
+
+func (durationCodec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tDuration {
+		return bsoncodec.ValueDecoderError{Name: "DurationDecodeValue", Types: []reflect.Type{tDuration}, Received: val}
+	}
+	var d time.Duration
+	switch vr.Type() {
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		if d, err = time.ParseDuration(s); err != nil {
+			return err
+		}
+	case bsontype.Int64:
+		n, err := vr.ReadInt64()
+		if err != nil {
+			return err
+		}
+		d = time.Duration(n)
+	case bsontype.Int32:
+		n, err := vr.ReadInt32()
+		if err != nil {
+			return err
+		}
+		d = time.Duration(n)
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return err
+		}
+		d = time.Duration(f)
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into a time.Duration", vr.Type())
+	}
+	val.SetInt(int64(d))
+	return nil
+}
+
+// ipCodec encodes net.IP values as strings
+type ipCodec struct{}
+
+var tIP = reflect.TypeOf(net.IP{})
+
+func (ipCodec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tIP {
+		return bsoncodec.ValueEncoderError{Name: "IPEncodeValue", Types: []reflect.Type{tIP}, Received: val}
+	}
+	if val.IsNil() {
+		return vw.WriteNull()
+	}
+	return vw.WriteString(val.Interface().(net.IP).String())
+}
+
+func (ipCodec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tIP {
+		return bsoncodec.ValueDecoderError{Name: "IPDecodeValue", Types: []reflect.Type{tIP}, Received: val}
+	}
+	var ip net.IP
+	switch vr.Type() {
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		if ip = net.ParseIP(s); ip == nil {
+			return fmt.Errorf("'%s' is not a valid IP address", s)
+		}
+	case bsontype.Binary:
+		b, _, err := vr.ReadBinary()
+		if err != nil {
+			return err
+		}
+		ip = append(net.IP(nil), b...)
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into a net.IP", vr.Type())
+	}
+	val.Set(reflect.ValueOf(ip))
+	return nil
+}
@@ -0,0 +1,68 @@
+package easymongo_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// uuid mirrors the layout of github.com/google/uuid.UUID
+type uuid [16]byte
+
+type server struct {
+	ID      uuid          `bson:"_id"`
+	Timeout time.Duration `bson:"timeout"`
+	IP      net.IP        `bson:"ip"`
+}
+
+func TestCodecs(t *testing.T) {
+	is := assert.New(t)
+	codecs := []easymongo.TypeCodec{easymongo.UUIDCodec(reflect.TypeOf(uuid{})), easymongo.DurationCodec(), easymongo.IPCodec()}
+	rb := bson.NewRegistryBuilder()
+	for _, codec := range codecs {
+		rb.RegisterTypeEncoder(codec.Type, codec.Codec)
+		rb.RegisterTypeDecoder(codec.Type, codec.Codec)
+	}
+	registry := rb.Build()
+
+	original := server{
+		ID:      uuid{0xde, 0xad, 0xbe, 0xef, 15: 0x01},
+		Timeout: 90 * time.Second,
+		IP:      net.ParseIP("10.0.0.1"),
+	}
+	raw, err := bson.MarshalWithRegistry(registry, original)
+	is.NoError(err)
+	id := bson.Raw(raw).Lookup("_id")
+	is.Equal(bsontype.Binary, id.Type)
+	subtype, _ := id.Binary()
+	is.Equal(bsontype.BinaryUUID, subtype, "UUIDs should be stored as binary subtype 4")
+	is.Equal("1m30s", bson.Raw(raw).Lookup("timeout").StringValue())
+	is.Equal("10.0.0.1", bson.Raw(raw).Lookup("ip").StringValue())
+
+	var decoded server
+	is.NoError(bson.UnmarshalWithRegistry(registry, raw, &decoded))
+	is.Equal(original.ID, decoded.ID)
+	is.Equal(original.Timeout, decoded.Timeout)
+	is.True(original.IP.Equal(decoded.IP))
+
+	// Values stored using the driver's defaults can still be decoded
+	legacy, err := bson.Marshal(bson.M{"timeout": int64(time.Minute), "ip": []byte(net.ParseIP("10.0.0.2"))})
+	is.NoError(err)
+	is.NoError(bson.UnmarshalWithRegistry(registry, legacy, &decoded))
+	is.Equal(time.Minute, decoded.Timeout)
+	is.True(net.ParseIP("10.0.0.2").Equal(decoded.IP))
+
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").SkipGlobal().
+		Codecs(codecs...).
+		RegisterTypeCodec(reflect.TypeOf(uuid{}), easymongo.UUIDCodec(reflect.TypeOf(uuid{})).Codec).
+		Connect()
+	is.NoError(err)
+	is.NoError(tmpConn.Close(context.Background()))
+}
@@ -2,12 +2,16 @@ package easymongo
 
 import (
 	"context"
-	"errors"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -16,7 +20,34 @@ import (
 type Collection struct {
 	database       *Database
 	collectionName string
-	mongoColl      *mongo.Collection
+	opts           *options.CollectionOptions
+	// mongoColl caches the driver collection for the client it was derived from
+	mongoColl *mongo.Collection
+	// flagsErr is set when WithFlags was passed conflicting flags
+	flagsErr error
+	lock     sync.Mutex
+}
+
+// flagsError returns the error operations on the collection fail with should it (or its database) have been
+// given conflicting flags
+func (c *Collection) flagsError() error {
+	if c.flagsErr != nil {
+		return c.flagsErr
+	}
+	return c.database.flagsErr
+}
+
+// mongoCollection returns the driver collection for the connection's current client.
+// The driver collection is re-derived should the client change (e.g. after a credential rotation),
+// so Collection handles remain valid for the life of the Connection.
+func (c *Collection) mongoCollection() *mongo.Collection {
+	mongoDB := c.database.mongoDatabase()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.mongoColl == nil || c.mongoColl.Database() != mongoDB {
+		c.mongoColl = mongoDB.Collection(c.collectionName, c.opts)
+	}
+	return c.mongoColl
 }
 
 // Name returns the name of the Collection in scope
@@ -34,12 +65,12 @@ func (c *Collection) Connection() *Connection {
 // If attempting to generate a context from a Query function, instead of this
 // use Query.getContext(). That will also account for any user provided context.
 func (c *Collection) defaultQueryCtx() (context.Context, context.CancelFunc) {
-	return c.database.connection.defaultQueryCtx()
+	return c.database.defaultQueryCtx()
 }
 
 // operationCtx returns a context based on if a OperationTimeout was specified.
 func (c *Collection) operationCtx() (context.Context, context.CancelFunc) {
-	return c.database.connection.operationCtx()
+	return c.database.operationCtx()
 }
 
 // GetDatabase returns the database associated with the database/collection.
@@ -58,16 +89,65 @@ func GetCollection(dbName, collectionName string) *Collection {
 func (c *Collection) Drop() (err error) {
 	ctx, cancelFunc := c.operationCtx()
 	defer cancelFunc()
-	return c.mongoColl.Drop(ctx)
+	return c.run(ctx, opDropCollection, nil, func(ctx context.Context) error {
+		return c.mongoCollection().Drop(ctx)
+	})
 }
 
 // MongoDriverCollection returns the native mongo driver collection object
 // (should you wish to interact with it directly)
 func (c *Collection) MongoDriverCollection() *mongo.Collection {
-	return c.mongoColl
+	return c.mongoCollection()
+}
+
+// With returns a copy of the collection that is bound to the provided transaction.
+// Queries built from the returned Collection run inside of the transaction.
+func (c *Collection) With(tx *Tx) *Collection {
+	return &Collection{
+		database:       c.database.With(tx),
+		collectionName: c.collectionName,
+		opts:           c.opts,
+		flagsErr:       c.flagsErr,
+	}
 }
 
-// func (c *Collection) With(s *Session) *Collection {return }
+// withOptions returns a copy of the collection using opts merged over the collection's own options
+func (c *Collection) withOptions(opts *options.CollectionOptions) *Collection {
+	return &Collection{
+		database:       c.database,
+		collectionName: c.collectionName,
+		opts:           options.MergeCollectionOptions(c.opts, opts),
+		flagsErr:       c.flagsErr,
+	}
+}
+
+// WithFlags returns a copy of the collection using the read concern, read preference and write concern
+// set in flags. Settings which are not in flags are inherited from the collection (and then its database).
+// Should the flags conflict (see ConnectionFlag.Validate), every query run using the returned Collection fails
+// with the validation error.
+// e.g. cache := conn.Database("app").C("cache").WithFlags(WriteConcernW1)
+func (c *Collection) WithFlags(flags ConnectionFlag) *Collection {
+	flagged := c.withOptions(flags.mongoDriverCollectionOptions())
+	if err := flags.Validate(); err != nil {
+		flagged.flagsErr = err
+	}
+	return flagged
+}
+
+// WithReadPreference returns a copy of the collection which reads using rp.
+func (c *Collection) WithReadPreference(rp *readpref.ReadPref) *Collection {
+	return c.withOptions(options.Collection().SetReadPreference(rp))
+}
+
+// WithReadConcern returns a copy of the collection which reads using rc.
+func (c *Collection) WithReadConcern(rc *readconcern.ReadConcern) *Collection {
+	return c.withOptions(options.Collection().SetReadConcern(rc))
+}
+
+// WithWriteConcern returns a copy of the collection which writes using wc.
+func (c *Collection) WithWriteConcern(wc *writeconcern.WriteConcern) *Collection {
+	return c.withOptions(options.Collection().SetWriteConcern(wc))
+}
 
 // func (c *Collection) EnsureIndexKey(key ...string) error {return }
 // func (c *Collection) EnsureIndex(index Index) error {return }
@@ -103,9 +183,13 @@ func (c *Collection) Count() (int, error) {
 // EstimatedCount returns the estimated count of the documents in the collection
 // For a precise count, try collection.Count()
 func (c *Collection) EstimatedCount() (int, error) {
+	var count int64
 	ctx, cancelFunc := c.defaultQueryCtx()
 	defer cancelFunc()
-	count, err := c.mongoColl.EstimatedDocumentCount(ctx)
+	err := c.run(ctx, opEstimatedCount, nil, func(ctx context.Context) (err error) {
+		count, err = c.mongoCollection().EstimatedDocumentCount(ctx)
+		return err
+	})
 	err = c.handleErr(err)
 	return int(count), err
 }
@@ -137,12 +221,14 @@ type CollectionStats struct {
 // Stats returns various stats representing metadata in a collection.
 func (c *Collection) Stats(adminName, promptName, collectionName string) (*CollectionStats, error) {
 	stats := &CollectionStats{}
-	err := c.MongoDriverCollection().Database().RunCommand(context.Background(), bson.D{
-		{Key: "collStats", Value: collectionName},
-		// Scale of 1 -> bytes being returned - let's use MB
-		// TODO: Use an iota const for this
-		{Key: "scale", Value: 1024 * 1024},
-	}).Decode(&stats)
+	err := c.Connection().track(func() error {
+		return c.MongoDriverCollection().Database().RunCommand(context.Background(), bson.D{
+			{Key: "collStats", Value: collectionName},
+			// Scale of 1 -> bytes being returned - let's use MB
+			// TODO: Use an iota const for this
+			{Key: "scale", Value: 1024 * 1024},
+		}).Decode(&stats)
+	})
 	stats.CollectionName = collectionName
 	return stats, err
 }
@@ -223,20 +309,7 @@ func (c *Collection) ReplaceByID(id interface{}, obj interface{}) (err error) {
 	return c.Replace(bson.M{"_id": id}, obj).One()
 }
 
-// handleErr
-// TODO: inject ErrNotFound
+// handleErr maps driver errors to easymongo errors (see MongoErr). Deadlines become ErrTimeoutOccurred.
 func (c *Collection) handleErr(origErr error) error {
-	if origErr == nil {
-		return nil
-	}
-	if errors.Is(origErr, context.DeadlineExceeded) {
-		return ErrTimeoutOccurred
-	}
-	// switch err := origErr.(type) {
-	// case mongo.CommandError:
-
-	// 	// if
-	// default:
-	// }
-	return origErr
+	return classifyErr(origErr)
 }
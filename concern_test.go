@@ -0,0 +1,44 @@
+package easymongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func TestConcernOverrides(t *testing.T) {
+	is := assert.New(t)
+	// The handles are never used to query, so nothing needs to be listening
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").SkipGlobal().Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+
+	db := tmpConn.Database("app")
+	// The client's defaults apply until a setting is overridden
+	clientReadConcern := db.MongoDriverDatabase().ReadConcern()
+	audit := db.WithFlags(easymongo.WriteConcernMajority | easymongo.ReadConcernMajority)
+	is.Equal(writeconcern.New(writeconcern.WMajority()), audit.MongoDriverDatabase().WriteConcern())
+	is.Equal(readconcern.Majority(), audit.MongoDriverDatabase().ReadConcern())
+
+	reports := audit.WithReadPreference(readpref.SecondaryPreferred())
+	is.Equal(readpref.SecondaryPreferred().Mode(), reports.MongoDriverDatabase().ReadPreference().Mode())
+	is.Equal(readconcern.Majority(), reports.MongoDriverDatabase().ReadConcern(), "Unset settings should be inherited")
+
+	snapshot := db.WithReadConcern(readconcern.Snapshot()).WithWriteConcern(writeconcern.New(writeconcern.W(2)))
+	is.Equal(readconcern.Snapshot(), snapshot.MongoDriverDatabase().ReadConcern())
+	is.Equal(writeconcern.New(writeconcern.W(2)), snapshot.MongoDriverDatabase().WriteConcern())
+	is.Equal("app", snapshot.Name())
+	is.Equal(clientReadConcern, db.MongoDriverDatabase().ReadConcern(), "The original handle should not change")
+
+	cache := db.C("cache")
+	w1 := cache.WithFlags(easymongo.WriteConcernW1).WithReadConcern(readconcern.Local())
+	is.NotSame(cache, w1)
+	is.Equal("cache", w1.Name())
+	is.Same(db, w1.GetDatabase())
+	is.NotNil(w1.WithWriteConcern(writeconcern.New(writeconcern.W(2))).WithReadPreference(readpref.Nearest()).MongoDriverCollection())
+}
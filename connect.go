@@ -2,22 +2,19 @@ package easymongo
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"errors"
-	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonoptions"
-	"go.mongodb.org/mongo-driver/event"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // noopCancelFunc is a helper for representing a no-op function (so that we can call defer cancel() contexts without panicking)
@@ -32,13 +29,53 @@ type MongoConnectOptions struct {
 	defaultOperationTimeout *time.Duration
 	// if a nil slice should encode as null instead of an empty array type, this should be true
 	nilSlicesAreNull *bool
+	// if a nil map should encode as null instead of an empty document, this should be true
+	nilMapsAreNull *bool
+	// codecs are added to the registry after easymongo's own codecs
+	codecs []TypeCodec
 	// This is used as the writeconcern w value which requests acknowledgement that write operations propagate to the specified number of mongod instances
 	numWritesForConsensus      *int
 	runHealthCheckOnConnection bool
-	connectionFlag             *ConnectionFlag
+	// healthCheckInterval is how often the background health check runs
+	healthCheckInterval time.Duration
+	connectionFlag      *ConnectionFlag
 	// Controls whether debug printing is enabled
 	debugMode bool
-	auth      *options.Credential
+	// auth holds the credentials the current client was connected with
+	auth *options.Credential
+	// credentialProvider supplies auth when connecting
+	credentialProvider CredentialProvider
+	// credentialRotationInterval is how often credentialProvider is polled for new credentials
+	credentialRotationInterval *time.Duration
+	// tlsOptions holds file based TLS settings
+	tlsOptions *TLSConfig
+	// tlsConfig is a user constructed TLS configuration
+	tlsConfig *tls.Config
+	// x509Auth derives credentials from the client certificate when true
+	x509Auth bool
+	// name is the name the connection is registered under in the global connection registry
+	name string
+	// isDefault makes the connection the default in the registry once connected
+	isDefault bool
+	// skipGlobal keeps the connection out of the registry entirely
+	skipGlobal bool
+	// maxPoolSize, minPoolSize, maxConnIdleTime and heartbeatInterval tune the driver connection pool
+	maxPoolSize       *uint64
+	minPoolSize       *uint64
+	maxConnIdleTime   *time.Duration
+	heartbeatInterval *time.Duration
+	// maxConnecting is accepted for forward compatibility - mongo-driver v1.7 cannot apply it
+	maxConnecting *uint64
+	// slowQueryThreshold is how long a command may take before it is reported as slow
+	slowQueryThreshold *time.Duration
+	// slowQueryOverrides holds per-collection slow query thresholds, keyed by collection name or "database.collection"
+	slowQueryOverrides map[string]time.Duration
+	// tracer wraps every terminal operation in a span
+	tracer Tracer
+	// retryPolicy retries operations which fail with a transient error
+	retryPolicy *RetryPolicy
+	// strictNotFound returns ErrNoDocuments from writes which match no documents
+	strictNotFound bool
 }
 
 // // RawMongoResult is used to represent the raw result that was returned from mongo
@@ -77,15 +114,18 @@ type MongoConnectOptions struct {
 // }
 
 // clientOptions returns the standard options.ClientOptions that mongo driver is looking for
-func (conn *Connection) clientOptions() *options.ClientOptions {
+func (conn *Connection) clientOptions() (*options.ClientOptions, error) {
 	var opts *options.ClientOptions
 	if conn.mongoOptions.connectionFlag != nil {
 		opts = conn.mongoOptions.connectionFlag.mongoDriverClientOptions().ApplyURI(conn.mongoOptions.mongoURI)
 	} else {
 		opts = DefaultAnywhere.mongoDriverClientOptions().ApplyURI(conn.mongoOptions.mongoURI)
 	}
-	if conn.mongoOptions.auth != nil {
-		opts.SetAuth(*conn.mongoOptions.auth)
+	conn.clientLock.RLock()
+	auth := conn.mongoOptions.auth
+	conn.clientLock.RUnlock()
+	if auth != nil {
+		opts.SetAuth(*auth)
 	}
 
 	registry := bson.NewRegistryBuilder()
@@ -100,10 +140,19 @@ func (conn *Connection) clientOptions() *options.ClientOptions {
 		registry.RegisterDefaultEncoder(reflect.Slice, nilSliceCodec)
 		registry.RegisterDefaultDecoder(reflect.Slice, nilSliceCodec)
 	}
+	if conn.mongoOptions.nilMapsAreNull == nil || !*conn.mongoOptions.nilMapsAreNull {
+		// Likewise, nil maps are saved as empty documents so that $set on a sub-key (e.g. "attrs.color") works
+		nilMapCodec := bsoncodec.NewMapCodec(bsonoptions.MapCodec().SetEncodeNilAsEmpty(true))
+		registry.RegisterDefaultEncoder(reflect.Map, nilMapCodec)
+		registry.RegisterDefaultDecoder(reflect.Map, nilMapCodec)
+	}
 
 	// m := RawMongoResult{}
 	// t := reflect.TypeOf(m)
 	// registry.RegisterHookDecoder(t, m)
+	for _, codec := range conn.mongoOptions.codecs {
+		codec.register(registry)
+	}
 	opts.SetRegistry(registry.Build())
 
 	if conn.mongoOptions.connectTimeout != nil {
@@ -115,95 +164,20 @@ func (conn *Connection) clientOptions() *options.ClientOptions {
 	if conn.mongoOptions.numWritesForConsensus != nil {
 		opts.SetWriteConcern(writeconcern.New(writeconcern.W(*conn.mongoOptions.numWritesForConsensus)))
 	}
-	if conn.mongoOptions.debugMode {
-		// Debug mode is enabled
-		monitor := &event.CommandMonitor{
-			Started: func(_ context.Context, e *event.CommandStartedEvent) {
-				out := map[string]interface{}{}
-				err := json.Unmarshal([]byte(e.Command.String()), &out)
-				if err != nil {
-					fmt.Println(e.Command)
-					panic(err)
-				}
-				bytes, _ := json.MarshalIndent(out, "  ", "\t")
-				conn.log.Debugf("Command executing against DB: '%s' Command: %s", e.DatabaseName, string(bytes))
-			},
-			Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
-				out := map[string]interface{}{}
-				err := json.Unmarshal([]byte(e.Reply.String()), &out)
-				if err != nil {
-					conn.log.Errorf("Could not parse DB reply into a map: %w. Raw reply: %s", err, e.Reply)
-				}
-				// There's more in e.Reply than just the actual data that's returned to be unpacked if it's
-				// and aggregation query.
-				// Use a type assertion to filter that data down.
-				var bytes []byte
-				if cursorData, found := out["cursor"]; found {
-					if returnedResult, isMap := cursorData.(map[string]interface{}); isMap {
-						if val, ok := returnedResult["firstBatch"]; ok {
-							bytes, _ = json.MarshalIndent(val, "  ", "\t")
-						}
-					}
-				} else {
-					bytes, _ = json.MarshalIndent(out, "  ", "\t")
-				}
-				conn.log.Debugf("DB execution success: Result: %s", string(bytes))
-			},
-			Failed: func(_ context.Context, e *event.CommandFailedEvent) {
-				conn.log.Errorf("DB command failed: %s", e.Failure)
-			},
-		}
-		opts.SetMonitor(monitor)
+	opts.SetMonitor(conn.commandMonitor())
+	opts.SetPoolMonitor(conn.pool.poolEventMonitor())
+	conn.mongoOptions.applyPool(opts)
+	if conn.mongoOptions.maxConnecting != nil {
+		conn.logger().Warnf("MaxConnecting requires mongo-driver v1.8 or later and is ignored by this version.")
 	}
 
-	// TODO: TLS config
-	// tlsConfig := &tls.Config{}
-	// if opts.SSLAllowInvalidCert || opts.SSLAllowInvalidHost {
-	// 	tlsConfig.InsecureSkipVerify = true
-	// }
-	// if opts.SSLPEMKeyFile != "" {
-	// 	_, err := addClientCertFromFile(tlsConfig, opts.SSLPEMKeyFile, opts.SSLPEMKeyPassword)
-	// 	if err != nil {
-	// 		return nil, fmt.Errorf("error configuring client, can't load client certificate: %v", err)
-	// 	}
-	// }
-	// if opts.SSLCAFile != "" {
-	// 	if err := addCACertFromFile(tlsConfig, opts.SSLCAFile); err != nil {
-	// 		return nil, fmt.Errorf("error configuring client, can't load CA file: %v", err)
-	// 	}
-	// }
-	// mopts.SetTLSConfig(tlsConfig)
+	if err := conn.mongoOptions.applyTLS(opts); err != nil {
+		return nil, err
+	}
 
-	return opts
+	return opts, nil
 }
 
-// // addCACertFromFile adds a root CA certificate to the configuration given a path
-// // to the containing file.
-// func addCACertFromFile(cfg *tls.Config, file string) error {
-// 	data, err := ioutil.ReadFile(file)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	certBytes, err := loadCert(data)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	cert, err := x509.ParseCertificate(certBytes)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	if cfg.RootCAs == nil {
-// 		cfg.RootCAs = x509.NewCertPool()
-// 	}
-
-// 	cfg.RootCAs.AddCert(cert)
-
-// 	return nil
-// }
-
 // Connect connects to the given mongo URI.
 // Connect wraps ConnectWith(mongoUri).Connect(). If you are using just a mongoUri for connection, this function should be all you
 // need. However, if you need to configure additional options, it is recommened to instead use ConnectWith().Connect().
@@ -235,11 +209,57 @@ func ConnectWith(mongoURI string) *ConnectionBuilder {
 
 // Flags can be used to set one or more connection flags. Consider using Default* options, or use bitwise '|' to specify multiple options
 // e.g.: ConnectWith(mongoURI).Flags(ReadConcernMajority | ReadPreferenceNearest | WriteConcernMajority)
+// Connect() fails if the flags conflict (see ConnectionFlag.Validate). ParseConnectionFlags reads flags from configuration.
 func (cb *ConnectionBuilder) Flags(flags ConnectionFlag) *ConnectionBuilder {
 	cb.connection.mongoOptions.connectionFlag = &flags
 	return cb
 }
 
+// NilSlicesAreNull controls how nil slices are stored. By default easymongo stores them as empty arrays,
+// so operators such as $push work on them later. Pass true to store them as null (the mongo-go-driver default).
+func (cb *ConnectionBuilder) NilSlicesAreNull(nilSlicesAreNull bool) *ConnectionBuilder {
+	cb.connection.mongoOptions.nilSlicesAreNull = &nilSlicesAreNull
+	return cb
+}
+
+// NilMapsAreNull controls how nil maps are stored. By default easymongo stores them as empty documents,
+// so a $set on a sub-key (e.g. "attrs.color") works later. Pass true to store them as null (the mongo-go-driver default).
+func (cb *ConnectionBuilder) NilMapsAreNull(nilMapsAreNull bool) *ConnectionBuilder {
+	cb.connection.mongoOptions.nilMapsAreNull = &nilMapsAreNull
+	return cb
+}
+
+// StrictNotFound makes UpdateQuery, DeleteQuery and ReplaceQuery return ErrNoDocuments when their filter matches
+// no documents (and nothing was upserted), just like FindQuery.One. By default, matching nothing is not an error.
+// This can be overridden per query using ErrorIfNotFound() or IgnoreNotFound().
+func (cb *ConnectionBuilder) StrictNotFound() *ConnectionBuilder {
+	cb.connection.mongoOptions.strictNotFound = true
+	return cb
+}
+
+// Name registers the connection under the provided name, so it can later be retrieved using GetConnection(name).
+// This is useful when talking to multiple clusters at the same time:
+// e.g. ConnectWith(analyticsURI).Name("analytics").Connect() then GetDatabaseOn("analytics", "reports")
+// If Name is not specified, the connection is registered as DefaultConnectionName.
+func (cb *ConnectionBuilder) Name(name string) *ConnectionBuilder {
+	cb.connection.mongoOptions.name = name
+	return cb
+}
+
+// AsDefault designates the connection as the default once connected, meaning it will be returned by
+// GetCurrentConnection() and consumed by GetDatabase() and GetCollection().
+func (cb *ConnectionBuilder) AsDefault() *ConnectionBuilder {
+	cb.connection.mongoOptions.isDefault = true
+	return cb
+}
+
+// SkipGlobal opts the connection out of the global connection registry. The connection is only
+// accessible using the value returned from Connect().
+func (cb *ConnectionBuilder) SkipGlobal() *ConnectionBuilder {
+	cb.connection.mongoOptions.skipGlobal = true
+	return cb
+}
+
 // DefaultQueryTimeout allows you to specify a timeout used for query operations.
 func (cb *ConnectionBuilder) DefaultQueryTimeout(timeout time.Duration) *ConnectionBuilder {
 	cb.connection.mongoOptions.defaultQueryTimeout = &timeout
@@ -272,11 +292,31 @@ func (cb *ConnectionBuilder) FromMongoDriverClient(client *mongo.Client) *Connec
 }
 
 // Connect performs the actual connection to the DB. A note that calling this function has the
-// side-effect of setting the global cached connection to this value. If you are not using the global
-// connection value and instead using the value explicitly returned from this function, then disregard this side-effect.
+// side-effect of registering this value in the global connection registry (and, unless Name() was used,
+// making it the default connection). If you are not using the global connection value and instead using the value
+// explicitly returned from this function, then disregard this side-effect or use SkipGlobal().
 // If a connection does not succeed, then an error is returned.
 func (cb *ConnectionBuilder) Connect() (*Connection, error) {
-	opts := cb.connection.clientOptions()
+	if flags := cb.connection.mongoOptions.connectionFlag; flags != nil {
+		if err := flags.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	cred, err := cb.connection.fetchCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if cred != nil {
+		cb.connection.mongoOptions.auth = cred
+	}
+	if cb.connection.mongoOptions.debugMode {
+		cb.connection.enableDebugObserver()
+	}
+	cb.connection.enableSlowQueryMonitor()
+	opts, err := cb.connection.clientOptions()
+	if err != nil {
+		return nil, err
+	}
 	if cb.connection.client == nil {
 		client, err := mongo.NewClient(opts)
 		if err != nil {
@@ -290,6 +330,12 @@ func (cb *ConnectionBuilder) Connect() (*Connection, error) {
 	if err := cb.connection.client.Connect(ctx); err != nil {
 		return nil, err
 	}
+	if interval := cb.connection.mongoOptions.credentialRotationInterval; interval != nil && cb.connection.mongoOptions.credentialProvider != nil {
+		watchCtx, stopWatching := context.WithCancel(context.Background())
+		cb.connection.stopCredentialWatch = stopWatching
+		go cb.connection.watchCredentials(watchCtx, *interval)
+	}
+	cb.connection.startHealthMonitor()
 	setGlobalConnection(&cb.connection)
 	return &cb.connection, nil
 }
@@ -299,42 +345,95 @@ type Connection struct {
 	mongoOptions MongoConnectOptions
 	client       *mongo.Client
 	log          Logger
+	// clientLock guards client, which may be swapped (e.g. when credentials rotate), and pinned
+	clientLock sync.RWMutex
+	// pinned tracks the transactions running on each client, so a swapped out client is not
+	// disconnected until they have finished
+	pinned map[*mongo.Client]*sync.WaitGroup
+	// stopCredentialWatch stops polling the CredentialProvider (if credential rotation is enabled)
+	stopCredentialWatch context.CancelFunc
+	// health holds the result of the most recent health check
+	health healthMonitor
+	// pool keeps the counters returned by PoolStats
+	pool poolMonitor
+	// metrics holds the metrics returned by Metrics
+	metrics metricsRegistry
+	// observers are notified of every command sent to the server
+	observers commandObservers
+	// slowQueries reports commands exceeding the slow query threshold (should one be configured)
+	slowQueries *slowQueryMonitor
+	// breaker fails operations fast while the cluster is unavailable (should CircuitBreaker be used)
+	breaker circuitBreaker
+	// lifecycle tracks in-flight operations so the connection can be closed gracefully
+	lifecycle lifecycle
+}
+
+// mongoClient returns the mongo.Client currently in use by the connection.
+func (conn *Connection) mongoClient() *mongo.Client {
+	conn.clientLock.RLock()
+	defer conn.clientLock.RUnlock()
+	return conn.client
+}
+
+// pinClient returns the mongo.Client currently in use by the connection and keeps it from being
+// disconnected (should it be swapped out) until release is called.
+func (conn *Connection) pinClient() (client *mongo.Client, release func()) {
+	conn.clientLock.Lock()
+	defer conn.clientLock.Unlock()
+	if conn.pinned == nil {
+		conn.pinned = map[*mongo.Client]*sync.WaitGroup{}
+	}
+	wg, ok := conn.pinned[conn.client]
+	if !ok {
+		wg = &sync.WaitGroup{}
+		conn.pinned[conn.client] = wg
+	}
+	wg.Add(1)
+	return conn.client, wg.Done
 }
 
-// EnableDebug enables debug, regenerates the client options
-// and reconnects to the mongo client.
+// swapClient replaces the mongo.Client in use by the connection and returns the previous client.
+// Database and Collection handles pick up the new client on their next operation.
+func (conn *Connection) swapClient(client *mongo.Client) (previous *mongo.Client) {
+	conn.clientLock.Lock()
+	defer conn.clientLock.Unlock()
+	previous = conn.client
+	conn.client = client
+	return previous
+}
+
+// EnableDebug logs every command the server runs (and its reply) to the debug level.
+// It takes effect immediately - the client is not rebuilt. Fields matched by ConnectionBuilder.Redact()
+// (as well as password and pwd) are masked.
 // This should never be done in a production environment unless you're, you know, debugging.
 // The connection is cached as the new global connection
-// If a logger has not been set (using conn.SetLogger)
+// If a logger has not been set (using conn.SetLogger), a DefaultLogger is used.
 func (conn *Connection) EnableDebug() error {
-	// Turn on debug mode
 	conn.mongoOptions.debugMode = true
-	ctx, cancelFunc := conn.operationCtx()
-	defer cancelFunc()
-	// Create a new client
-	client, err := mongo.NewClient(conn.clientOptions())
-	if err != nil {
-		return err
-	}
-	// Drop this client into the connection obect
-	*conn.client = *client
-	if conn.log == nil {
-		conn.SetLogger(NewDefaultLogger())
-		conn.log.Debugf("No logger was set using Connection.SetLogger(). Initialized a new one for debugging.")
-	}
-	// And reconnect to the client
-	if err = conn.client.Connect(ctx); err != nil {
-		return err
-	}
+	conn.enableDebugObserver()
 	setGlobalConnection(conn)
-
 	return nil
 }
 
+// DisableDebug stops logging the commands the server runs.
+func (conn *Connection) DisableDebug() {
+	conn.disableDebugObserver()
+	conn.mongoOptions.debugMode = false
+}
+
 // SetLogger overrides a connection's logger object
 // This is used primarily when enabling debug mode
-func (conn *Connection) SetLogger(logger Logger) {
-	conn.log = logger
+// A SimpleLogger (which only supports Debugf() and Errorf()) is wrapped so it can be used as a Logger.
+func (conn *Connection) SetLogger(logger SimpleLogger) {
+	conn.log = toLogger(logger)
+}
+
+// Name returns the name the connection is registered under in the global connection registry.
+func (conn *Connection) Name() string {
+	if conn.mongoOptions.name == "" {
+		return DefaultConnectionName
+	}
+	return conn.mongoOptions.name
 }
 
 // MongoURI returns the URI that the mongo instance is connected to
@@ -348,8 +447,15 @@ func (conn *Connection) MongoURI() string {
 func (conn *Connection) Ping() (err error) {
 	ctx, cancel := conn.operationCtx()
 	defer cancel()
+	return conn.ping(ctx)
+}
 
-	err = conn.client.Ping(ctx, conn.clientOptions().ReadPreference)
+// ping checks the connection to the cluster using ctx
+func (conn *Connection) ping(ctx context.Context) (err error) {
+	err = conn.track(func() error {
+		// A nil ReadPreference falls back to the client's default
+		return conn.mongoClient().Ping(ctx, nil)
+	})
 	if err != nil && errors.Is(err, context.DeadlineExceeded) {
 		err = ErrTimeoutOccurred
 	}
@@ -360,16 +466,33 @@ func (conn *Connection) Ping() (err error) {
 // for direct interaction with the mongo driver for those users searching for
 // more fine-grained control.
 func (conn *Connection) MongoDriverClient() *mongo.Client {
-	return conn.client
+	return conn.mongoClient()
+}
+
+// disconnectClient disconnects a mongo.Client which is no longer in use by the connection.
+// Transactions still running on the client are allowed to finish first.
+// Operations still running against the client are given the operation timeout to complete.
+func (conn *Connection) disconnectClient(client *mongo.Client) {
+	conn.clientLock.Lock()
+	wg := conn.pinned[client]
+	delete(conn.pinned, client)
+	conn.clientLock.Unlock()
+	if wg != nil {
+		wg.Wait()
+	}
+	ctx, cancel := conn.operationCtx()
+	defer cancel()
+	if err := client.Disconnect(ctx); err != nil {
+		conn.logger().WithField("error", err).Errorf("Could not disconnect the previous mongo client: %v", err)
+	}
 }
 
 // Database returns the database object associated with the provided database name
 func (conn *Connection) Database(dbName string) *Database {
-	opts := options.Database()
 	return &Database{
 		connection: conn,
 		dbName:     dbName,
-		mongoDB:    conn.client.Database(dbName, opts),
+		opts:       options.Database(),
 	}
 }
 
@@ -379,6 +502,8 @@ func (conn *Connection) D(dbName string) *Database {
 	return conn.Database(dbName)
 }
 
+// ConnectionFlag configures the read concern, read preference and write concern of a connection, database,
+// collection or query. Combine flags using bitwise '|' or parse them using ParseConnectionFlags.
 type ConnectionFlag int32
 
 const (
@@ -394,6 +519,7 @@ const (
 	ReadPreferencePrimary
 	// ReadPreferencePrimaryPreferred prefers reads from primary, but will fall back
 	ReadPreferencePrimaryPreferred
+	// ReadPreferenceWriteConcern does not map to a read preference and is rejected by Validate
 	ReadPreferenceWriteConcern
 	ReadPreferenceSecondary
 	ReadPreferenceSecondaryPreferred
@@ -426,12 +552,14 @@ func (cb *ConnectionBuilder) Debug() *ConnectionBuilder {
 }
 
 // Logger overrides the default logger in use
+// A SimpleLogger (which only supports Debugf() and Errorf()) is wrapped so it can be used as a Logger.
+// A *logrus.Logger or *logrus.Entry is adapted using NewLogrusLogger.
 // TODO: Handle nil/deactivating logger
-func (cb *ConnectionBuilder) Logger(logger Logger) *ConnectionBuilder {
+func (cb *ConnectionBuilder) Logger(logger SimpleLogger) *ConnectionBuilder {
 	if logger == nil {
 		cb.connection.log = NewDefaultLogger()
 	} else {
-		cb.connection.log = logger
+		cb.connection.log = toLogger(logger)
 	}
 
 	return cb
@@ -440,48 +568,31 @@ func (cb *ConnectionBuilder) Logger(logger Logger) *ConnectionBuilder {
 // mongoDriverDatabaseOptions returns the associated options for the provided connectFlag(s)
 func (connectFlag ConnectionFlag) mongoDriverDatabaseOptions() *options.DatabaseOptions {
 	opts := options.Database()
-	wOpts := make([]writeconcern.Option, 0)
-
-	switch {
-	case connectFlag&ReadConcernAvailable != 0:
-		opts.SetReadConcern(readconcern.Available())
-	case connectFlag&ReadConcernLinearizable != 0:
-		opts.SetReadConcern(readconcern.Linearizable())
-	case connectFlag&ReadConcernLocal != 0:
-		opts.SetReadConcern(readconcern.Local())
-	case connectFlag&ReadConcernMajority != 0:
-		opts.SetReadConcern(readconcern.Majority())
-	case connectFlag&ReadConcernSnapshot != 0:
-		opts.SetReadConcern(readconcern.Snapshot())
-	}
-	switch {
-	case connectFlag&ReadPreferenceNearest != 0:
-		opts.SetReadPreference(readpref.Nearest())
-	case connectFlag&ReadPreferencePrimary != 0:
-		opts.SetReadPreference(readpref.Primary())
-	case connectFlag&ReadPreferencePrimaryPreferred != 0:
-		opts.SetReadPreference(readpref.PrimaryPreferred())
-	case connectFlag&ReadPreferenceSecondary != 0:
-		opts.SetReadPreference(readpref.Secondary())
-	case connectFlag&ReadPreferenceSecondaryPreferred != 0:
-		opts.SetReadPreference(readpref.SecondaryPreferred())
-	}
-
-	if connectFlag&WriteConcernJournal != 0 {
-		wOpts = append(wOpts, writeconcern.J(true))
-	}
-	if connectFlag&WriteConcernW1 != 0 {
-		wOpts = append(wOpts, writeconcern.W(1))
-	} else if connectFlag&WriteConcernW2 != 0 {
-		wOpts = append(wOpts, writeconcern.W(2))
-	} else if connectFlag&WriteConcernW3 != 0 {
-		wOpts = append(wOpts, writeconcern.W(3))
-	}
-	if connectFlag&WriteConcernMajority != 0 {
-		wOpts = append(wOpts, writeconcern.WMajority())
-	}
-	if len(wOpts) != 0 {
-		opts.SetWriteConcern(writeconcern.New(wOpts...))
+	rc, rp, wc := connectFlag.concerns()
+	if rc != nil {
+		opts.SetReadConcern(rc)
+	}
+	if rp != nil {
+		opts.SetReadPreference(rp)
+	}
+	if wc != nil {
+		opts.SetWriteConcern(wc)
+	}
+	return opts
+}
+
+// mongoDriverCollectionOptions returns the associated options for the provided connectFlag(s)
+func (connectFlag ConnectionFlag) mongoDriverCollectionOptions() *options.CollectionOptions {
+	opts := options.Collection()
+	rc, rp, wc := connectFlag.concerns()
+	if rc != nil {
+		opts.SetReadConcern(rc)
+	}
+	if rp != nil {
+		opts.SetReadPreference(rp)
+	}
+	if wc != nil {
+		opts.SetWriteConcern(wc)
 	}
 	return opts
 }
@@ -489,50 +600,15 @@ func (connectFlag ConnectionFlag) mongoDriverDatabaseOptions() *options.Database
 // mongoDriverClientOptions returns the associated options for the provided connectFlag(s)
 func (connectFlag ConnectionFlag) mongoDriverClientOptions() *options.ClientOptions {
 	opts := options.Client()
-	wOpts := make([]writeconcern.Option, 0)
-
-	switch {
-	case connectFlag&ReadConcernAvailable != 0:
-		opts.SetReadConcern(readconcern.Available())
-	case connectFlag&ReadConcernLinearizable != 0:
-		opts.SetReadConcern(readconcern.Linearizable())
-	case connectFlag&ReadConcernLocal != 0:
-		opts.SetReadConcern(readconcern.Local())
-	case connectFlag&ReadConcernMajority != 0:
-		opts.SetReadConcern(readconcern.Majority())
-	case connectFlag&ReadConcernSnapshot != 0:
-		opts.SetReadConcern(readconcern.Snapshot())
-	}
-	switch {
-	case connectFlag&ReadPreferenceNearest != 0:
-		opts.SetReadPreference(readpref.Nearest())
-	case connectFlag&ReadPreferencePrimary != 0:
-		opts.SetReadPreference(readpref.Primary())
-	case connectFlag&ReadPreferencePrimaryPreferred != 0:
-		opts.SetReadPreference(readpref.PrimaryPreferred())
-	case connectFlag&ReadPreferenceSecondary != 0:
-		opts.SetReadPreference(readpref.Secondary())
-	case connectFlag&ReadPreferenceSecondaryPreferred != 0:
-		opts.SetReadPreference(readpref.SecondaryPreferred())
-	}
-
-	if connectFlag&WriteConcernJournal != 0 {
-		wOpts = append(wOpts, writeconcern.J(true))
-	}
-	if connectFlag&WriteConcernW1 != 0 {
-		wOpts = append(wOpts, writeconcern.W(1))
-	}
-	if connectFlag&WriteConcernW2 != 0 {
-		wOpts = append(wOpts, writeconcern.W(2))
-	}
-	if connectFlag&WriteConcernW3 != 0 {
-		wOpts = append(wOpts, writeconcern.W(3))
-	}
-	if connectFlag&WriteConcernMajority != 0 {
-		wOpts = append(wOpts, writeconcern.WMajority())
-	}
-	if len(wOpts) != 0 {
-		opts.SetWriteConcern(writeconcern.New(wOpts...))
+	rc, rp, wc := connectFlag.concerns()
+	if rc != nil {
+		opts.SetReadConcern(rc)
+	}
+	if rp != nil {
+		opts.SetReadPreference(rp)
+	}
+	if wc != nil {
+		opts.SetWriteConcern(wc)
 	}
 	return opts
 }
@@ -542,7 +618,7 @@ func (conn *Connection) DatabaseByConnectionType(dbName string, connectFlag Conn
 	return &Database{
 		connection: conn,
 		dbName:     dbName,
-		mongoDB:    conn.client.Database(dbName, connectFlag.mongoDriverDatabaseOptions()),
+		opts:       connectFlag.mongoDriverDatabaseOptions(),
 	}
 }
 
@@ -552,19 +628,17 @@ func (conn *Connection) DatabaseNames() []string {
 	opts := options.ListDatabases()
 	ctx, cancel := conn.operationCtx()
 	defer cancel()
-	list, err := conn.client.ListDatabaseNames(ctx, bson.M{}, opts)
+	var list []string
+	err := conn.track(func() (err error) {
+		list, err = conn.mongoClient().ListDatabaseNames(ctx, bson.M{}, opts)
+		return err
+	})
 	if err != nil {
 		list = []string{}
 	}
 	return list
 }
 
-// getDefaultQueryCtx returns a context based on if a default query timeout has been set.
-// context.Background() and an empty inlined function are returned if no timeout has been set.
-func (conn *Connection) defaultQueryCtx() (ctx context.Context, cancel context.CancelFunc) {
-	return GetTimeoutCtx(conn.mongoOptions.defaultQueryTimeout)
-}
-
 // operationCtx returns a context based on if a default operation timeout has been set.
 // context.Background() and an empty inlined function are returned if no timeout has been set.
 func (conn *Connection) operationCtx() (ctx context.Context, cancel context.CancelFunc) {
@@ -574,14 +648,18 @@ func (conn *Connection) operationCtx() (ctx context.Context, cancel context.Canc
 // GetTimeoutCtx returns a context based on if a timeout has been specified. If no timeout
 // was specified, then context.Background() is returned.
 func GetTimeoutCtx(timeout *time.Duration) (ctx context.Context, cancel context.CancelFunc) {
-	ctx = context.Background()
+	return getTimeoutCtx(context.Background(), timeout)
+}
+
+// getTimeoutCtx derives a context from parent based on if a timeout has been specified. If no timeout
+// was specified, then parent is returned.
+func getTimeoutCtx(parent context.Context, timeout *time.Duration) (ctx context.Context, cancel context.CancelFunc) {
 	// Make cancel a no-op function by default to avoid possible nil function calls
 	// Empty inlined functions end up no-oped by compiler
 	if timeout != nil {
-		// TODO: Should this be a
-		return context.WithTimeout(context.Background(), *timeout)
+		return context.WithTimeout(parent, *timeout)
 	}
-	return ctx, noopCancelFunc
+	return parent, noopCancelFunc
 }
 
 // ListDatabases returns a list of databases available in the connected cluster as objects that can be interacted with.
@@ -1,10 +1,229 @@
 package easymongo
 
-import "go.mongodb.org/mongo-driver/mongo/options"
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 
-// TODO: Something to help with credentials
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
 
-// WithAuth injects credentials into mongoOptions
-func (c *ConnectionBuilder) WithAuth(creds *options.Credential) {
-	c.connection.mongoOptions.auth = creds
+// CredentialProvider supplies the credentials used when authenticating with mongo.
+// Implementations must be safe for concurrent use, as the provider is polled in the
+// background when credential rotation is enabled.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (*options.Credential, error)
+}
+
+// WithAuth injects static credentials into mongoOptions
+// It is equivalent to calling Credentials(StaticCredentials(*creds)).
+func (cb *ConnectionBuilder) WithAuth(creds *options.Credential) *ConnectionBuilder {
+	if creds == nil {
+		cb.connection.mongoOptions.credentialProvider = nil
+		return cb
+	}
+	return cb.Credentials(StaticCredentials(*creds))
+}
+
+// Credentials sets the provider credentials are loaded from when connecting.
+// e.g. ConnectWith(mongoURI).Credentials(FileCredentials("/var/run/secrets/mongo")).RotateCredentials(time.Minute).Connect()
+func (cb *ConnectionBuilder) Credentials(provider CredentialProvider) *ConnectionBuilder {
+	cb.connection.mongoOptions.credentialProvider = provider
+	return cb
+}
+
+// RotateCredentials polls the CredentialProvider at the provided interval. Should the credentials change,
+// a new client is connected using them and swapped in for the old client. Existing Database and Collection
+// handles keep working and use the new client for their next operation.
+func (cb *ConnectionBuilder) RotateCredentials(interval time.Duration) *ConnectionBuilder {
+	cb.connection.mongoOptions.credentialRotationInterval = &interval
+	return cb
+}
+
+// staticCredentials is a CredentialProvider which always returns the same credentials
+type staticCredentials struct {
+	cred options.Credential
+}
+
+// StaticCredentials returns a CredentialProvider which always supplies the provided credentials.
+func StaticCredentials(cred options.Credential) CredentialProvider {
+	return &staticCredentials{cred: cred}
+}
+
+// Credentials returns a copy of the static credentials
+func (sc *staticCredentials) Credentials(_ context.Context) (*options.Credential, error) {
+	cred := sc.cred
+	return &cred, nil
+}
+
+// EnvCredentialProvider loads the username and password from environment variables.
+type EnvCredentialProvider struct {
+	// UsernameVar is the name of the environment variable holding the username
+	UsernameVar string
+	// PasswordVar is the name of the environment variable holding the password
+	PasswordVar string
+	// AuthSource is the name of the database to authenticate against. If unset, the driver default is used.
+	AuthSource string
+	// AuthMechanism is the mechanism to authenticate with. If unset, the driver default is used.
+	AuthMechanism string
+}
+
+// EnvCredentials returns a CredentialProvider which loads credentials from the named environment variables.
+// e.g. EnvCredentials("MONGO_USERNAME", "MONGO_PASSWORD")
+func EnvCredentials(usernameVar, passwordVar string) *EnvCredentialProvider {
+	return &EnvCredentialProvider{
+		UsernameVar: usernameVar,
+		PasswordVar: passwordVar,
+	}
+}
+
+// Credentials reads the credentials from the environment
+func (ep *EnvCredentialProvider) Credentials(_ context.Context) (*options.Credential, error) {
+	username, found := os.LookupEnv(ep.UsernameVar)
+	if !found {
+		return nil, fmt.Errorf("the environment variable '%s' holding the mongo username is not set", ep.UsernameVar)
+	}
+	password, passwordSet := os.LookupEnv(ep.PasswordVar)
+	return &options.Credential{
+		AuthMechanism: ep.AuthMechanism,
+		AuthSource:    ep.AuthSource,
+		Username:      username,
+		Password:      password,
+		PasswordSet:   passwordSet,
+	}, nil
+}
+
+// FileCredentialProvider loads the username and password from files. This is typically used with
+// a mounted Kubernetes secret, where each key of the secret is a file in the mounted directory.
+type FileCredentialProvider struct {
+	// Dir is the directory containing the credential files
+	Dir string
+	// UsernameFile is the name of the file (relative to Dir) holding the username. Defaults to "username".
+	UsernameFile string
+	// PasswordFile is the name of the file (relative to Dir) holding the password. Defaults to "password".
+	PasswordFile string
+	// AuthSource is the name of the database to authenticate against. If unset, the driver default is used.
+	AuthSource string
+	// AuthMechanism is the mechanism to authenticate with. If unset, the driver default is used.
+	AuthMechanism string
+}
+
+// FileCredentials returns a CredentialProvider which loads credentials from the "username" and "password"
+// files in the provided directory.
+func FileCredentials(dir string) *FileCredentialProvider {
+	return &FileCredentialProvider{
+		Dir:          dir,
+		UsernameFile: "username",
+		PasswordFile: "password",
+	}
+}
+
+// Credentials reads the credentials from the filesystem
+func (fp *FileCredentialProvider) Credentials(_ context.Context) (*options.Credential, error) {
+	username, err := readCredentialFile(filepath.Join(fp.Dir, fp.UsernameFile))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the mongo username: %w", err)
+	}
+	password, err := readCredentialFile(filepath.Join(fp.Dir, fp.PasswordFile))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the mongo password: %w", err)
+	}
+	return &options.Credential{
+		AuthMechanism: fp.AuthMechanism,
+		AuthSource:    fp.AuthSource,
+		Username:      username,
+		Password:      password,
+		PasswordSet:   true,
+	}, nil
+}
+
+// readCredentialFile returns the contents of a file with any trailing newline removed
+func readCredentialFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// fetchCredentials loads credentials from the configured CredentialProvider.
+// nil is returned if no provider was configured.
+func (conn *Connection) fetchCredentials() (*options.Credential, error) {
+	if conn.mongoOptions.credentialProvider == nil {
+		return nil, nil
+	}
+	ctx, cancel := conn.operationCtx()
+	defer cancel()
+	return conn.mongoOptions.credentialProvider.Credentials(ctx)
+}
+
+// watchCredentials polls the CredentialProvider until ctx is cancelled, rotating the client
+// whenever the credentials change.
+func (conn *Connection) watchCredentials(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.rotateCredentials(); err != nil {
+				conn.logger().WithField("error", err).Errorf("Could not rotate mongo credentials: %v", err)
+			}
+		}
+	}
+}
+
+// rotateCredentials connects a new client if the credentials supplied by the provider have changed.
+// The new client is only swapped in once it has successfully pinged the server, so a bad set of
+// credentials does not take down a working connection.
+func (conn *Connection) rotateCredentials() error {
+	cred, err := conn.fetchCredentials()
+	if err != nil {
+		return err
+	}
+	conn.clientLock.RLock()
+	unchanged := reflect.DeepEqual(cred, conn.mongoOptions.auth)
+	conn.clientLock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	opts, err := conn.clientOptions()
+	if err != nil {
+		return err
+	}
+	if cred != nil && !conn.mongoOptions.x509Auth {
+		opts.SetAuth(*cred)
+	}
+	client, err := mongo.NewClient(opts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := conn.operationCtx()
+	defer cancel()
+	if err = client.Connect(ctx); err != nil {
+		return err
+	}
+	if err = client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(ctx)
+		return err
+	}
+
+	conn.clientLock.Lock()
+	previous := conn.client
+	conn.client = client
+	conn.mongoOptions.auth = cred
+	conn.clientLock.Unlock()
+	conn.logger().Infof("Mongo credentials changed - the connection is now using a new client.")
+	if previous != nil {
+		go conn.disconnectClient(previous)
+	}
+	return nil
 }
@@ -0,0 +1,54 @@
+package easymongo_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCredentials(t *testing.T) {
+	ctx := context.Background()
+	t.Run("StaticCredentials", func(t *testing.T) {
+		is := assert.New(t)
+		provider := easymongo.StaticCredentials(options.Credential{Username: "alfred", Password: "pennyworth"})
+		cred, err := provider.Credentials(ctx)
+		is.NoError(err)
+		is.Equal("alfred", cred.Username)
+		is.Equal("pennyworth", cred.Password)
+	})
+	t.Run("EnvCredentials", func(t *testing.T) {
+		is := assert.New(t)
+		os.Setenv("EASYMONGO_TEST_USERNAME", "bruce")
+		os.Setenv("EASYMONGO_TEST_PASSWORD", "iambatman")
+		t.Cleanup(func() {
+			os.Unsetenv("EASYMONGO_TEST_USERNAME")
+			os.Unsetenv("EASYMONGO_TEST_PASSWORD")
+		})
+		cred, err := easymongo.EnvCredentials("EASYMONGO_TEST_USERNAME", "EASYMONGO_TEST_PASSWORD").Credentials(ctx)
+		is.NoError(err)
+		is.Equal("bruce", cred.Username)
+		is.Equal("iambatman", cred.Password)
+
+		_, err = easymongo.EnvCredentials("EASYMONGO_TEST_MISSING", "EASYMONGO_TEST_PASSWORD").Credentials(ctx)
+		is.Error(err, "A missing username variable should be an error")
+	})
+	t.Run("FileCredentials", func(t *testing.T) {
+		is := assert.New(t)
+		dir := t.TempDir()
+		is.NoError(ioutil.WriteFile(filepath.Join(dir, "username"), []byte("dick\n"), 0600))
+		is.NoError(ioutil.WriteFile(filepath.Join(dir, "password"), []byte("robin\n"), 0600))
+		cred, err := easymongo.FileCredentials(dir).Credentials(ctx)
+		is.NoError(err)
+		is.Equal("dick", cred.Username, "The trailing newline should be trimmed")
+		is.Equal("robin", cred.Password, "The trailing newline should be trimmed")
+
+		_, err = easymongo.FileCredentials(filepath.Join(dir, "missing")).Credentials(ctx)
+		is.Error(err, "A missing secret directory should be an error")
+	})
+}
@@ -1,24 +1,69 @@
 package easymongo
 
 import (
+	"context"
+	"sync"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Database is helper for representing a database in a cluster.
 type Database struct {
 	connection *Connection
 	dbName     string
-	mongoDB    *mongo.Database
+	opts       *options.DatabaseOptions
+	// mongoDB caches the driver database for the client it was derived from
+	mongoDB *mongo.Database
+	// tx is set when the database is bound to a transaction
+	tx *Tx
+	// flagsErr is set when WithFlags was passed conflicting flags. Operations fail with it rather than
+	// running with whichever setting happened to win.
+	flagsErr error
+	lock     sync.Mutex
+}
+
+// track runs fn as an in-flight operation on the database's connection (see Connection.trackIn).
+// fn is not run should the database have been given conflicting flags.
+func (db *Database) track(fn func() error) error {
+	if db.flagsErr != nil {
+		return db.flagsErr
+	}
+	return db.connection.trackIn(db.tx, fn)
+}
+
+// mongoDatabase returns the driver database for the connection's current client (or the client
+// the transaction was started on, should the database be bound to one).
+// The driver database is re-derived should the client change (e.g. after a credential rotation).
+func (db *Database) mongoDatabase() *mongo.Database {
+	client := db.connection.mongoClient()
+	if db.tx != nil {
+		client = db.tx.client
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	if db.mongoDB == nil || db.mongoDB.Client() != client {
+		db.mongoDB = client.Database(db.dbName, db.opts)
+	}
+	return db.mongoDB
 }
 
-// GetDatabase returns a database object for the named database using the most recently connected to
-// mongo instance/cluster.
+// GetDatabase returns a database object for the named database using the default connection
+// (typically the most recently connected to mongo instance/cluster).
 func GetDatabase(dbName string) *Database {
 	return GetCurrentConnection().Database(dbName)
 }
 
+// GetDatabaseOn returns a database object for the named database using the connection registered
+// under connectionName.
+func GetDatabaseOn(connectionName, dbName string) *Database {
+	return GetConnection(connectionName).Database(dbName)
+}
+
 // C returns a Collection object that can be used to run queries on and create/drop indices
 // C wraps Collection() for users who want to use short-hand to do queries.
 // It is interchangeable with a call to db.Collection().
@@ -29,21 +74,24 @@ func (db *Database) C(name string) *Collection {
 // Collection returns a Collection object that can be used to run queries on and create/drop indices
 // It is interchangeable with C().
 func (db *Database) Collection(name string) *Collection {
-	opts := options.Collection()
 	return &Collection{
 		database:       db,
 		collectionName: name,
-		mongoColl:      db.mongoDB.Collection(name, opts),
+		opts:           options.Collection(),
 	}
 }
 
 // CollectionNames returns the names of the collections as strings.
 // If no collections could be found, then an empty list is returned.
 func (db *Database) CollectionNames() []string {
-	ctx, cancelFunc := db.connection.operationCtx()
+	ctx, cancelFunc := db.operationCtx()
 	defer cancelFunc()
 	opts := options.ListCollections().SetNameOnly(true)
-	collectionNames, err := db.mongoDB.ListCollectionNames(ctx, bson.M{}, opts)
+	var collectionNames []string
+	err := db.track(func() (err error) {
+		collectionNames, err = db.mongoDatabase().ListCollectionNames(ctx, bson.M{}, opts)
+		return err
+	})
 	if err != nil {
 		return []string{}
 	}
@@ -64,15 +112,93 @@ func (db *Database) ListCollections() ([]*Collection, error) {
 	return colls, nil
 }
 
-// TODO: DB.With
-// func (db *Database) With(s *Session) *Database {return }
+// With returns a copy of the database that is bound to the provided transaction.
+// Any Collection obtained from the returned Database will run its queries inside of the transaction.
+func (db *Database) With(tx *Tx) *Database {
+	return &Database{
+		connection: db.connection,
+		dbName:     db.dbName,
+		opts:       db.opts,
+		tx:         tx,
+		flagsErr:   db.flagsErr,
+	}
+}
+
+// withOptions returns a copy of the database using opts merged over the database's own options
+func (db *Database) withOptions(opts *options.DatabaseOptions) *Database {
+	return &Database{
+		connection: db.connection,
+		dbName:     db.dbName,
+		opts:       options.MergeDatabaseOptions(db.opts, opts),
+		tx:         db.tx,
+		flagsErr:   db.flagsErr,
+	}
+}
+
+// WithFlags returns a copy of the database using the read concern, read preference and write concern
+// set in flags. Settings which are not in flags are kept. Collections obtained from the returned Database
+// inherit the settings. Should the flags conflict (see ConnectionFlag.Validate), every operation run using the
+// returned Database (or its Collections) fails with the validation error.
+// e.g. auditDB := conn.Database("audit").WithFlags(WriteConcernMajority | ReadConcernMajority)
+func (db *Database) WithFlags(flags ConnectionFlag) *Database {
+	flagged := db.withOptions(flags.mongoDriverDatabaseOptions())
+	if err := flags.Validate(); err != nil {
+		flagged.flagsErr = err
+	}
+	return flagged
+}
+
+// WithReadPreference returns a copy of the database which reads using rp.
+func (db *Database) WithReadPreference(rp *readpref.ReadPref) *Database {
+	return db.withOptions(options.Database().SetReadPreference(rp))
+}
+
+// WithReadConcern returns a copy of the database which reads using rc.
+func (db *Database) WithReadConcern(rc *readconcern.ReadConcern) *Database {
+	return db.withOptions(options.Database().SetReadConcern(rc))
+}
+
+// WithWriteConcern returns a copy of the database which writes using wc.
+func (db *Database) WithWriteConcern(wc *writeconcern.WriteConcern) *Database {
+	return db.withOptions(options.Database().SetWriteConcern(wc))
+}
+
+// baseCtx returns the context that operations against this database derive from.
+// If the database is bound to a transaction, this is the transaction's session context.
+func (db *Database) baseCtx() context.Context {
+	if db.tx != nil {
+		return db.tx.sessionCtx
+	}
+	return context.Background()
+}
+
+// bindSession attaches the transaction session (if any) to a user supplied context.
+func (db *Database) bindSession(ctx context.Context) context.Context {
+	if db.tx != nil {
+		return mongo.NewSessionContext(ctx, db.tx.sessionCtx)
+	}
+	return ctx
+}
+
+// operationCtx returns a context based on if a OperationTimeout was specified.
+func (db *Database) operationCtx() (context.Context, context.CancelFunc) {
+	return getTimeoutCtx(db.baseCtx(), db.connection.mongoOptions.defaultOperationTimeout)
+}
+
+// defaultQueryCtx returns a context based on if a default query timeout was specified.
+func (db *Database) defaultQueryCtx() (context.Context, context.CancelFunc) {
+	return getTimeoutCtx(db.baseCtx(), db.connection.mongoOptions.defaultQueryTimeout)
+}
+
 // TODO: DB.GridFS
 // func (db *Database) GridFS(prefix string) *GridFS {return }
 // TODO: DB.Run
 func (db *Database) Run(cmd interface{}, result interface{}) error {
-	ctx, cancelFunc := db.connection.defaultQueryCtx()
+	ctx, cancelFunc := db.defaultQueryCtx()
 	defer cancelFunc()
-	return db.mongoDB.RunCommand(ctx, cmd).Decode(result)
+	return db.track(func() error {
+		return db.mongoDatabase().RunCommand(ctx, cmd).Decode(result)
+	})
 }
 
 // TODO: DB.Login
@@ -92,9 +218,17 @@ func (db *Database) Run(cmd interface{}, result interface{}) error {
 
 // Drop drops a database from a mongo instance. Use with caution.
 func (db *Database) Drop() error {
-	ctx, cancel := db.connection.operationCtx()
+	ctx, cancel := db.operationCtx()
 	defer cancel()
-	return db.mongoDB.Drop(ctx)
+	return db.track(func() error {
+		return db.mongoDatabase().Drop(ctx)
+	})
+}
+
+// MongoDriverDatabase returns the mongo.Database object from the underlying mongo driver
+// (should you wish to interact with it directly)
+func (db *Database) MongoDriverDatabase() *mongo.Database {
+	return db.mongoDatabase()
 }
 
 // Name returns the name of the database
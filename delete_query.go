@@ -1,9 +1,15 @@
 package easymongo
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // DeleteQuery stores the data necessary to execute a deletion. collection.Delete() returns an initialized DeleteQuery.
@@ -33,27 +39,33 @@ func (dq *DeleteQuery) deleteOptions() *options.DeleteOptions {
 
 // One calls out to DeleteOne() which deletes the first entry matching the
 // filter query provided to Delete().
+// ErrNoDocuments is returned if nothing was deleted when StrictNotFound or ErrorIfNotFound is set.
 func (dq *DeleteQuery) One() (err error) {
-	ctx, cancelFunc := dq.getContext()
-	defer cancelFunc()
+	var res *mongo.DeleteResult
 	opts := dq.deleteOptions()
-	_, err = dq.collection.mongoColl.DeleteOne(ctx, dq.filter, opts)
-	err = dq.collection.handleErr(err)
-	if err != nil {
-		return err
-	}
-	// TODO: Handle ErrNotFound
-	// if res.DeletedCount == 0 { err = ErrNotFound }
-	return err
+	err = dq.run(opDeleteOne, func(ctx context.Context) (err error) {
+		res, err = dq.collection.mongoCollection().DeleteOne(ctx, dq.filter, opts)
+		if err != nil {
+			return err
+		}
+		return dq.notFoundErr(res.DeletedCount)
+	})
+	return dq.collection.handleErr(err)
 }
 
 // Many calls out to DeleteMany() which deletes all entries matching the
 // filter query provided to Delete().
+// ErrNoDocuments is returned if nothing was deleted when StrictNotFound or ErrorIfNotFound is set.
 func (dq *DeleteQuery) Many() (numDeleted int, err error) {
-	ctx, cancelFunc := dq.getContext()
-	defer cancelFunc()
+	var res *mongo.DeleteResult
 	opts := dq.deleteOptions()
-	res, err := dq.collection.mongoColl.DeleteMany(ctx, dq.filter, opts)
+	err = dq.run(opDeleteMany, func(ctx context.Context) (err error) {
+		res, err = dq.collection.mongoCollection().DeleteMany(ctx, dq.filter, opts)
+		if err != nil {
+			return err
+		}
+		return dq.notFoundErr(res.DeletedCount)
+	})
 	err = dq.collection.handleErr(err)
 	if err != nil {
 		return numDeleted, err
@@ -61,8 +73,6 @@ func (dq *DeleteQuery) Many() (numDeleted int, err error) {
 	if res != nil {
 		numDeleted = int(res.DeletedCount)
 	}
-	// TODO: Handle ErrNotFound
-	// if res.DeletedCount == 0 { err = ErrNotFound }
 	return numDeleted, err
 }
 
@@ -80,3 +90,55 @@ func (dq *DeleteQuery) Hint(indexKeys ...string) *DeleteQuery {
 	dq.Query.setHint(indexKeys...)
 	return dq
 }
+
+// WithContext will consume the supplied context. A note that this is completely optional
+// as Timeout will auto-create a context for you if one is not supplied.
+// This is typically useful when using a context across many functions (e.g. to propagate a trace).
+func (dq *DeleteQuery) WithContext(ctx context.Context) *DeleteQuery {
+	dq.Query.setContext(&ctx)
+	return dq
+}
+
+// Retry overrides the connection's RetryPolicy for this query. Use RetryPolicy{MaxAttempts: 1} to disable retrying.
+func (dq *DeleteQuery) Retry(policy RetryPolicy) *DeleteQuery {
+	dq.Query.setRetry(policy)
+	return dq
+}
+
+// ReadPreference overrides the read preference for this query only.
+// e.g. ReadPreference(readpref.SecondaryPreferred())
+func (dq *DeleteQuery) ReadPreference(rp *readpref.ReadPref) *DeleteQuery {
+	dq.Query.setReadPreference(rp)
+	return dq
+}
+
+// ReadConcern overrides the read concern for this query only.
+func (dq *DeleteQuery) ReadConcern(rc *readconcern.ReadConcern) *DeleteQuery {
+	dq.Query.setReadConcern(rc)
+	return dq
+}
+
+// WriteConcern overrides the write concern for this query only.
+func (dq *DeleteQuery) WriteConcern(wc *writeconcern.WriteConcern) *DeleteQuery {
+	dq.Query.setWriteConcern(wc)
+	return dq
+}
+
+// Flags overrides the read concern, read preference and write concern set in flags for this query only.
+// e.g. Flags(ReadPreferenceSecondaryPreferred | ReadConcernMajority)
+func (dq *DeleteQuery) Flags(flags ConnectionFlag) *DeleteQuery {
+	dq.Query.setFlags(flags)
+	return dq
+}
+
+// ErrorIfNotFound returns ErrNoDocuments should the filter match no documents, regardless of StrictNotFound.
+func (dq *DeleteQuery) ErrorIfNotFound() *DeleteQuery {
+	dq.Query.setErrorIfNotFound(true)
+	return dq
+}
+
+// IgnoreNotFound does not return an error should the filter match no documents, regardless of StrictNotFound.
+func (dq *DeleteQuery) IgnoreNotFound() *DeleteQuery {
+	dq.Query.setErrorIfNotFound(false)
+	return dq
+}
@@ -0,0 +1,212 @@
+package easymongo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DuplicateKeyError describes a write which violated a unique index. It is returned by the single document
+// writes (e.g. InsertQuery.One, ReplaceQuery.One and upserts), while InsertQuery.Many returns DuplicateKeyErrors.
+// It matches ErrDuplicateKey using errors.Is.
+// e.g. var dupErr easymongo.DuplicateKeyError; if errors.As(err, &dupErr) { conflict(dupErr.Keys) }
+type DuplicateKeyError struct {
+	// Index is the name of the unique index which was violated (e.g. "email_1")
+	Index string
+	// Keys holds the indexed fields and the values which collided (e.g. {"email": "bruce@wayne.com"})
+	Keys map[string]interface{}
+	// WriteIndex is the position of the failed write within the documents passed to the query (0 for single writes)
+	WriteIndex int
+	// Message is the message returned by the server
+	Message string
+	err     error
+}
+
+func (de DuplicateKeyError) Error() string {
+	return de.Message
+}
+
+// Unwrap returns the MongoErr the duplicate key was parsed from
+func (de DuplicateKeyError) Unwrap() error {
+	return de.err
+}
+
+// DuplicateKeyErrors is returned by InsertQuery.Many should any of the documents violate a unique index.
+// It holds every failed position (a single position if the insert was ordered, as it stops at the first failure).
+// It matches ErrDuplicateKey using errors.Is.
+type DuplicateKeyErrors struct {
+	Errors []DuplicateKeyError
+	err    error
+}
+
+func (de DuplicateKeyErrors) Error() string {
+	if len(de.Errors) == 1 {
+		return de.Errors[0].Message
+	}
+	return fmt.Sprintf("%d documents violated a unique index - first error: %s", len(de.Errors), de.Errors[0].Message)
+}
+
+// Unwrap returns the MongoErr the duplicate keys were parsed from
+func (de DuplicateKeyErrors) Unwrap() error {
+	return de.err
+}
+
+// DuplicateKeys returns every duplicate key described by err, whichever query returned it.
+// nil is returned if err was not caused by a unique index violation.
+func DuplicateKeys(err error) []DuplicateKeyError {
+	var many DuplicateKeyErrors
+	if errors.As(err, &many) {
+		return many.Errors
+	}
+	var one DuplicateKeyError
+	if errors.As(err, &one) {
+		return []DuplicateKeyError{one}
+	}
+	return parseDuplicateKeys(err)
+}
+
+// duplicateKeyErr replaces err with a DuplicateKeyError (or DuplicateKeyErrors for InsertQuery.Many)
+// should it be caused by a unique index violation
+func duplicateKeyErr(op operation, err error) error {
+	if !op.write || !IsDuplicateKey(err) {
+		return err
+	}
+	dups := parseDuplicateKeys(err)
+	switch {
+	case len(dups) == 0:
+		return err
+	case op == opInsertMany:
+		return DuplicateKeyErrors{Errors: dups, err: err}
+	}
+	return dups[0]
+}
+
+// parseDuplicateKeys returns a DuplicateKeyError for each unique index violation in err
+func parseDuplicateKeys(err error) []DuplicateKeyError {
+	var dups []DuplicateKeyError
+	add := func(writeIndex, code int, message string, details bson.Raw) {
+		if code != codeDuplicateKey && code != codeDuplicateKeyLegacy && code != codeDuplicateKeyUpdate {
+			return
+		}
+		dup := DuplicateKeyError{WriteIndex: writeIndex, Message: message, err: err}
+		dup.Index, dup.Keys = parseDuplicateKeyMessage(message)
+		if keyValue, ok := details.Lookup("keyValue").DocumentOK(); ok {
+			// Prefer the structured values, should the server supply them
+			keys := map[string]interface{}{}
+			if bson.Unmarshal(keyValue, &keys) == nil {
+				dup.Keys = keys
+			}
+		}
+		dups = append(dups, dup)
+	}
+
+	var cmdErr mongo.CommandError
+	var writeErr mongo.WriteException
+	var bulkErr mongo.BulkWriteException
+	switch {
+	case errors.As(err, &cmdErr):
+		add(0, int(cmdErr.Code), cmdErr.Message, nil)
+	case errors.As(err, &writeErr):
+		for _, we := range writeErr.WriteErrors {
+			add(we.Index, we.Code, we.Message, we.Details)
+		}
+	case errors.As(err, &bulkErr):
+		for _, we := range bulkErr.WriteErrors {
+			add(we.Index, we.Code, we.Message, we.Details)
+		}
+	}
+	return dups
+}
+
+// duplicateKeyMessage matches the index name and key of an E11000 message
+// e.g. E11000 duplicate key error collection: batman_archive.enemies index: name_1 dup key: { name: "Bane" }
+var duplicateKeyMessage = regexp.MustCompile(`index: (\S+) dup key: (\{.*\})`)
+
+// parseDuplicateKeyMessage extracts the index name and the colliding key values from an E11000 message.
+// Keys is nil if the message could not be parsed.
+func parseDuplicateKeyMessage(message string) (index string, keys map[string]interface{}) {
+	match := duplicateKeyMessage.FindStringSubmatch(message)
+	if match == nil {
+		return "", nil
+	}
+	index = match[1]
+	body := strings.TrimSpace(match[2][1 : len(match[2])-1])
+	keys = map[string]interface{}{}
+	for i, field := range splitTopLevel(body) {
+		colon := strings.Index(field, ":")
+		if colon < 0 {
+			continue
+		}
+		key := strings.Trim(strings.TrimSpace(field[:colon]), `"`)
+		if key == "" {
+			// Older servers omit the field names, so fall back to the position
+			key = strconv.Itoa(i)
+		}
+		keys[key] = parseShellValue(strings.TrimSpace(field[colon+1:]))
+	}
+	return index, keys
+}
+
+// splitTopLevel splits s on the commas which are not within quotes, braces, brackets or parentheses
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && inString:
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// objectIDValue matches an ObjectId in the mongo shell format
+var objectIDValue = regexp.MustCompile(`^ObjectId\(['"]([0-9a-fA-F]{24})['"]\)$`)
+
+// parseShellValue converts a value rendered in the mongo shell format to a Go value.
+// Values which can't be converted (e.g. documents) are returned as they were rendered.
+func parseShellValue(value string) interface{} {
+	if s, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return s
+	}
+	if match := objectIDValue.FindStringSubmatch(value); match != nil {
+		if id, err := primitive.ObjectIDFromHex(match[1]); err == nil {
+			return id
+		}
+	}
+	switch value {
+	case "null":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
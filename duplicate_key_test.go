@@ -0,0 +1,44 @@
+package easymongo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestDuplicateKeys(t *testing.T) {
+	is := assert.New(t)
+	id := primitive.NewObjectID()
+	bulkErr := mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{
+		{WriteError: mongo.WriteError{Index: 1, Code: 11000, Message: `E11000 duplicate key error collection: batman_archive.enemies index: name_1_alias_1 dup key: { name: "Bane, \"The Man\"", alias: null }`}},
+		{WriteError: mongo.WriteError{Index: 2, Code: 121, Message: "Document failed validation"}},
+		{WriteError: mongo.WriteError{Index: 4, Code: 11000, Message: "E11000 duplicate key error collection: batman_archive.enemies index: _id_ dup key: { _id: ObjectId('" + id.Hex() + "') }"}},
+	}}
+	dups := easymongo.DuplicateKeys(bulkErr)
+	if is.Len(dups, 2, "Only the duplicate key errors should be returned") {
+		is.Equal("name_1_alias_1", dups[0].Index)
+		is.Equal(1, dups[0].WriteIndex)
+		is.Equal(map[string]interface{}{"name": `Bane, "The Man"`, "alias": nil}, dups[0].Keys)
+		is.Equal("_id_", dups[1].Index)
+		is.Equal(4, dups[1].WriteIndex)
+		is.Equal(map[string]interface{}{"_id": id}, dups[1].Keys)
+		var driverErr mongo.BulkWriteException
+		is.True(errors.As(dups[0], &driverErr), "The driver error should still be available")
+	}
+
+	dups = easymongo.DuplicateKeys(easymongo.NewMongoErr(mongo.CommandError{Code: 11000,
+		Message: "E11000 duplicate key error collection: batman_archive.enemies index: timesFought_1_deceased_1 dup key: { : 3, : true }"}))
+	if is.Len(dups, 1, "findAndModify errors should be parsed") {
+		is.Equal("timesFought_1_deceased_1", dups[0].Index)
+		is.Equal(map[string]interface{}{"0": int64(3), "1": true}, dups[0].Keys, "Unnamed keys should fall back to their position")
+		is.True(easymongo.IsDuplicateKey(dups[0]))
+		is.True(errors.Is(dups[0], easymongo.ErrDuplicateKey))
+	}
+
+	is.Nil(easymongo.DuplicateKeys(nil))
+	is.Nil(easymongo.DuplicateKeys(easymongo.NewMongoErr(mongo.CommandError{Code: 112})))
+}
@@ -1,6 +1,7 @@
 package easymongo
 
 import (
+	"context"
 	"errors"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -8,13 +9,22 @@ import (
 
 // MongoErr represents any kind of mongo error, regardless of which
 // component of mongo-go-driver threw the error.
+// Errors returned by the server are wrapped in a MongoErr holding the server's error code, and match
+// the sentinel describing them using errors.Is (e.g. errors.Is(err, ErrDuplicateKey)).
+// The original driver error is available using errors.As (e.g. to a mongo.WriteException).
 type MongoErr struct {
 	Message string `json:"message"`
-	err     error
+	// Code is the server error code (0 if the error did not come from the server).
+	// For write errors, this is the code of the first failed write.
+	Code int `json:"code,omitempty"`
+	err  error
+	// kind is the err of the sentinel this error matches (if any)
+	kind error
 }
 
 // NewMongoErr casts any error to a MongoErr. This is intended
 // to help extrapolate all mongo errors into a single class.
+// The server error code is extracted and the error matches the sentinel describing it (e.g. ErrDuplicateKey).
 // Should be used as:
 // if v := errors.Is(err, mongo.Error) {
 //		merr := NewMongoErr(err)
@@ -23,7 +33,9 @@ type MongoErr struct {
 func NewMongoErr(err error) (me MongoErr) {
 	return MongoErr{
 		Message: err.Error(),
+		Code:    serverErrorCode(err),
 		err:     err,
+		kind:    errorKindOf(err),
 	}
 }
 func (me MongoErr) Error() string {
@@ -33,6 +45,38 @@ func (me MongoErr) Unwrap() error {
 	return me.err
 }
 
+// Is reports whether the error matches target, which is one of the sentinel errors (e.g. ErrDuplicateKey)
+func (me MongoErr) Is(target error) bool {
+	sentinel, ok := target.(MongoErr)
+	return ok && me.kind != nil && sentinel.err == me.kind
+}
+
+// Labels returns the labels the server attached to the error (e.g. "TransientTransactionError")
+func (me MongoErr) Labels() []string {
+	var cmdErr mongo.CommandError
+	var writeErr mongo.WriteException
+	var bulkErr mongo.BulkWriteException
+	switch {
+	case errors.As(me.err, &cmdErr):
+		return cmdErr.Labels
+	case errors.As(me.err, &writeErr):
+		return writeErr.Labels
+	case errors.As(me.err, &bulkErr):
+		return bulkErr.Labels
+	}
+	return nil
+}
+
+// HasLabel returns true if the server attached label to the error
+func (me MongoErr) HasLabel(label string) bool {
+	for _, l := range me.Labels() {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	// ErrNotImplemented is raised when a function is not yet supported/complete
 	// This is mostly used to help track development progress
@@ -45,4 +89,176 @@ var (
 	ErrNoDocuments = NewMongoErr(mongo.ErrNoDocuments)
 	// ErrWrongType indicates the specified distinct operation did not work. Check the field type that you are attempting to use distinct on.
 	ErrWrongType = NewMongoErr(errors.New("the type specified could not be decoded into"))
+	// ErrConnectionClosed denotes an operation was attempted after Connection.Close() was called
+	ErrConnectionClosed = NewMongoErr(errors.New("the connection has been closed"))
+	// ErrCircuitOpen denotes an operation was refused because the connection's circuit breaker is open
+	ErrCircuitOpen = NewMongoErr(errors.New("the circuit breaker is open - the cluster is unavailable"))
+	// ErrDuplicateKey denotes a write violated a unique index
+	ErrDuplicateKey = NewMongoErr(errDuplicateKey)
+	// ErrWriteConflict denotes a write conflicted with another operation (typically in a transaction)
+	ErrWriteConflict = NewMongoErr(errWriteConflict)
+	// ErrDocumentValidation denotes a write failed the collection's schema validation
+	ErrDocumentValidation = NewMongoErr(errDocumentValidation)
+	// ErrUnauthorized denotes the user is not allowed to run the operation
+	ErrUnauthorized = NewMongoErr(errUnauthorized)
+	// ErrAuthenticationFailed denotes the credentials were rejected
+	ErrAuthenticationFailed = NewMongoErr(errAuthenticationFailed)
+	// ErrNetwork denotes the server could not be reached or the connection to it failed
+	ErrNetwork = NewMongoErr(errNetwork)
+	// ErrNotPrimary denotes a write (or primary read) was sent to a node which is not, or is no longer, the primary
+	ErrNotPrimary = NewMongoErr(errNotPrimary)
+	// ErrExceededTimeLimit denotes the server aborted the operation after it exceeded its time limit (e.g. maxTimeMS)
+	ErrExceededTimeLimit = NewMongoErr(errExceededTimeLimit)
+	// ErrNamespaceNotFound denotes the database or collection does not exist
+	ErrNamespaceNotFound = NewMongoErr(errNamespaceNotFound)
+	// ErrIndexConflict denotes an index already exists with the same name or keys but different options
+	ErrIndexConflict = NewMongoErr(errIndexConflict)
+	// ErrCursorNotFound denotes a cursor was killed or timed out on the server
+	ErrCursorNotFound = NewMongoErr(errCursorNotFound)
+)
+
+// The errors matched by the sentinels describing server errors
+var (
+	errDuplicateKey         = errors.New("duplicate key")
+	errWriteConflict        = errors.New("write conflict")
+	errDocumentValidation   = errors.New("document failed validation")
+	errUnauthorized         = errors.New("unauthorized")
+	errAuthenticationFailed = errors.New("authentication failed")
+	errNetwork              = errors.New("network error")
+	errNotPrimary           = errors.New("not primary")
+	errExceededTimeLimit    = errors.New("exceeded time limit")
+	errNamespaceNotFound    = errors.New("namespace not found")
+	errIndexConflict        = errors.New("index conflict")
+	errCursorNotFound       = errors.New("cursor not found")
 )
+
+// Server error codes used to classify errors (in addition to those in retry.go)
+const (
+	codeUnauthorized          = 13
+	codeAuthenticationFailed  = 18
+	codeNamespaceNotFound     = 26
+	codeCursorNotFound        = 43
+	codeMaxTimeMSExpired      = 50
+	codeIndexOptionsConflict  = 85
+	codeIndexKeySpecsConflict = 86
+	codeDocumentValidation    = 121
+	codeDuplicateKey          = 11000
+	codeDuplicateKeyLegacy    = 11001
+	codeDuplicateKeyUpdate    = 12582
+)
+
+// errorKind maps server error codes to the sentinel they match
+type errorKind struct {
+	kind  error
+	codes []int
+}
+
+// errorKinds are checked in order, so the most specific kinds come first
+var errorKinds = []errorKind{
+	{errDuplicateKey, []int{codeDuplicateKey, codeDuplicateKeyLegacy, codeDuplicateKeyUpdate}},
+	{errWriteConflict, []int{codeWriteConflict}},
+	{errDocumentValidation, []int{codeDocumentValidation}},
+	{errUnauthorized, []int{codeUnauthorized}},
+	{errAuthenticationFailed, []int{codeAuthenticationFailed}},
+	{errNotPrimary, []int{codeNotWritablePrimary, codeNotPrimaryNoSecondaryOk, codeNotPrimaryOrSecondary,
+		codePrimarySteppedDown, codeInterruptedDueToReplStateChange}},
+	{errExceededTimeLimit, []int{codeMaxTimeMSExpired, codeExceededTimeLimit}},
+	{errNamespaceNotFound, []int{codeNamespaceNotFound}},
+	{errIndexConflict, []int{codeIndexOptionsConflict, codeIndexKeySpecsConflict}},
+	{errCursorNotFound, []int{codeCursorNotFound}},
+	{errNetwork, []int{codeHostUnreachable, codeHostNotFound, codeNetworkTimeout, codeSocketException}},
+}
+
+// IsDuplicateKey returns true if err was caused by a write violating a unique index
+func IsDuplicateKey(err error) bool {
+	return errors.Is(err, ErrDuplicateKey)
+}
+
+// IsWriteConflict returns true if err was caused by a write conflicting with another operation
+func IsWriteConflict(err error) bool {
+	return errors.Is(err, ErrWriteConflict)
+}
+
+// IsDocumentValidation returns true if err was caused by a write failing schema validation
+func IsDocumentValidation(err error) bool {
+	return errors.Is(err, ErrDocumentValidation)
+}
+
+// IsUnauthorized returns true if err was caused by the user lacking permission to run the operation
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsNetwork returns true if err was caused by the server being unreachable or a connection failing
+func IsNetwork(err error) bool {
+	return errors.Is(err, ErrNetwork)
+}
+
+// IsNotPrimary returns true if err was caused by the operation being sent to a node which is not the primary
+func IsNotPrimary(err error) bool {
+	return errors.Is(err, ErrNotPrimary)
+}
+
+// IsExceededTimeLimit returns true if err was caused by the server aborting the operation after its time limit
+func IsExceededTimeLimit(err error) bool {
+	return errors.Is(err, ErrExceededTimeLimit)
+}
+
+// serverErrorCode returns the code of a server error (0 if err did not come from the server)
+func serverErrorCode(err error) int {
+	var cmdErr mongo.CommandError
+	var writeErr mongo.WriteException
+	var bulkErr mongo.BulkWriteException
+	switch {
+	case errors.As(err, &cmdErr):
+		return int(cmdErr.Code)
+	case errors.As(err, &writeErr) && len(writeErr.WriteErrors) > 0:
+		return writeErr.WriteErrors[0].Code
+	case errors.As(err, &writeErr) && writeErr.WriteConcernError != nil:
+		return writeErr.WriteConcernError.Code
+	case errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0:
+		return bulkErr.WriteErrors[0].Code
+	case errors.As(err, &bulkErr) && bulkErr.WriteConcernError != nil:
+		return bulkErr.WriteConcernError.Code
+	}
+	return 0
+}
+
+// errorKindOf returns the error matched by the sentinel describing err (nil if there is none)
+func errorKindOf(err error) error {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		for _, kind := range errorKinds {
+			for _, code := range kind.codes {
+				if serverErr.HasErrorCode(code) {
+					return kind.kind
+				}
+			}
+		}
+	}
+	if mongo.IsNetworkError(err) {
+		return errNetwork
+	}
+	return nil
+}
+
+// classifyErr wraps errors returned by the server (and network errors) in a MongoErr which holds the server error
+// code and matches the sentinel describing the error. Deadlines become ErrTimeoutOccurred and mongo.ErrNoDocuments
+// becomes ErrNoDocuments (which still matches mongo.ErrNoDocuments). Other errors (including those which are
+// already a MongoErr or an OpError) are returned as is.
+func classifyErr(err error) error {
+	var me MongoErr
+	var opErr OpError
+	var serverErr mongo.ServerError
+	switch {
+	case err == nil, errors.As(err, &me), errors.As(err, &opErr):
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeoutOccurred
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return ErrNoDocuments
+	case errors.As(err, &serverErr), mongo.IsNetworkError(err):
+		return NewMongoErr(err)
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package easymongo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMongoErr(t *testing.T) {
+	is := assert.New(t)
+	dupErr := easymongo.NewMongoErr(mongo.WriteException{
+		WriteErrors: mongo.WriteErrors{{Index: 0, Code: 11000, Message: "E11000 duplicate key error collection: batman_archive.enemies index: name_1 dup key: { name: \"Bane\" }"}},
+		Labels:      []string{"SomeLabel"},
+	})
+	is.Equal(11000, dupErr.Code)
+	is.True(easymongo.IsDuplicateKey(dupErr))
+	is.True(errors.Is(dupErr, easymongo.ErrDuplicateKey))
+	is.False(errors.Is(dupErr, easymongo.ErrWriteConflict))
+	is.True(dupErr.HasLabel("SomeLabel"))
+	var writeErr mongo.WriteException
+	is.True(errors.As(dupErr, &writeErr), "The driver error should still be available")
+
+	bulkErr := easymongo.NewMongoErr(mongo.BulkWriteException{
+		WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Index: 1, Code: 121, Message: "Document failed validation"}}},
+	})
+	is.True(easymongo.IsDocumentValidation(bulkErr))
+	is.Equal(121, bulkErr.Code)
+
+	for code, sentinel := range map[int32]easymongo.MongoErr{
+		112:   easymongo.ErrWriteConflict,
+		13:    easymongo.ErrUnauthorized,
+		18:    easymongo.ErrAuthenticationFailed,
+		10107: easymongo.ErrNotPrimary,
+		189:   easymongo.ErrNotPrimary,
+		50:    easymongo.ErrExceededTimeLimit,
+		26:    easymongo.ErrNamespaceNotFound,
+		85:    easymongo.ErrIndexConflict,
+		43:    easymongo.ErrCursorNotFound,
+		9001:  easymongo.ErrNetwork,
+	} {
+		err := easymongo.NewMongoErr(mongo.CommandError{Code: code, Labels: []string{"TransientTransactionError"}})
+		is.True(errors.Is(err, sentinel), "Code %d should match %v", code, sentinel)
+		is.Equal(int(code), err.Code)
+		is.Equal([]string{"TransientTransactionError"}, err.Labels())
+	}
+	is.True(easymongo.IsNetwork(easymongo.NewMongoErr(mongo.CommandError{Labels: []string{"NetworkError"}})))
+	is.True(easymongo.IsWriteConflict(easymongo.NewMongoErr(mongo.CommandError{Code: 112})))
+	is.True(easymongo.IsUnauthorized(easymongo.NewMongoErr(mongo.CommandError{Code: 13})))
+	is.True(easymongo.IsNotPrimary(easymongo.NewMongoErr(mongo.CommandError{Code: 13435})))
+	is.True(easymongo.IsExceededTimeLimit(easymongo.NewMongoErr(mongo.CommandError{Code: 262})))
+
+	unknown := easymongo.NewMongoErr(errors.New("something else"))
+	is.Zero(unknown.Code)
+	is.Empty(unknown.Labels())
+	is.False(errors.Is(unknown, easymongo.ErrDuplicateKey))
+	is.True(errors.Is(easymongo.ErrDuplicateKey, easymongo.ErrDuplicateKey))
+	is.True(easymongo.IsRetryableError(easymongo.NewMongoErr(mongo.CommandError{Code: 112})), "Wrapped errors should still be retryable")
+}
@@ -0,0 +1,39 @@
+package easymongo
+
+import (
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ShouldRetry exposes RetryPolicy.shouldRetry to the tests. write selects a single document write
+// (InsertQuery.One) rather than a read (FindQuery.One).
+func (policy *RetryPolicy) ShouldRetry(write bool, err error) bool {
+	if write {
+		return policy.shouldRetry(opInsertOne, err)
+	}
+	return policy.shouldRetry(opFindOne, err)
+}
+
+// RotateClient swaps client in for the connection's current client and disconnects the previous one
+// in the background, as a credential rotation does.
+func (conn *Connection) RotateClient(client *mongo.Client) (previous *mongo.Client) {
+	previous = conn.swapClient(client)
+	go conn.disconnectClient(previous)
+	return previous
+}
+
+// RecordCircuit counts err against the connection's circuit breaker, as the result of an operation.
+func (conn *Connection) RecordCircuit(err error) {
+	conn.breaker.record(conn, err)
+}
+
+// CommandMonitor exposes the event.CommandMonitor installed on the connection's client, so events can be fed
+// to it without a server.
+func (conn *Connection) CommandMonitor() *event.CommandMonitor {
+	return conn.commandMonitor()
+}
+
+// PoolEvent feeds e to the connection's pool monitor, as the driver does.
+func (conn *Connection) PoolEvent(e *event.PoolEvent) {
+	conn.pool.poolEventMonitor().Event(e)
+}
@@ -0,0 +1,246 @@
+// Package filter builds query filters, so they don't have to be written by hand as nested bson.M values.
+// Every builder returns an ordered bson.D, which is accepted anywhere easymongo accepts a filter.
+// e.g. coll.Find(filter.And(filter.Eq("name", "The Joker"), filter.Gte("evilness", 90))).All(&enemies)
+// Use a Schema to check the field names of a filter against a struct's bson tags before it is run.
+package filter
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fieldOp returns a filter applying a single operator to field
+// e.g. {field: {operator: value}}
+func fieldOp(name, operator string, value interface{}) bson.D {
+	return bson.D{{Key: name, Value: bson.D{{Key: operator, Value: value}}}}
+}
+
+// Eq matches documents where field equals value.
+// e.g. Eq("name", "The Joker") is {"name": {"$eq": "The Joker"}}
+func Eq(field string, value interface{}) bson.D {
+	return fieldOp(field, "$eq", value)
+}
+
+// Ne matches documents where field does not equal value (including documents without the field).
+func Ne(field string, value interface{}) bson.D {
+	return fieldOp(field, "$ne", value)
+}
+
+// Gt matches documents where field is greater than value.
+func Gt(field string, value interface{}) bson.D {
+	return fieldOp(field, "$gt", value)
+}
+
+// Gte matches documents where field is greater than or equal to value.
+func Gte(field string, value interface{}) bson.D {
+	return fieldOp(field, "$gte", value)
+}
+
+// Lt matches documents where field is less than value.
+func Lt(field string, value interface{}) bson.D {
+	return fieldOp(field, "$lt", value)
+}
+
+// Lte matches documents where field is less than or equal to value.
+func Lte(field string, value interface{}) bson.D {
+	return fieldOp(field, "$lte", value)
+}
+
+// In matches documents where field equals any of the values. A single slice may be passed in place of the values.
+// e.g. In("name", "Bane", "Two-Face") or In("name", names)
+func In(field string, values ...interface{}) bson.D {
+	return fieldOp(field, "$in", array(values))
+}
+
+// Nin matches documents where field equals none of the values. A single slice may be passed in place of the values.
+func Nin(field string, values ...interface{}) bson.D {
+	return fieldOp(field, "$nin", array(values))
+}
+
+// All matches documents where the array field contains every one of the values.
+// A single slice may be passed in place of the values.
+func All(field string, values ...interface{}) bson.D {
+	return fieldOp(field, "$all", array(values))
+}
+
+// Exists matches documents which have field (if exists is true) or do not have it (if exists is false).
+func Exists(field string, exists bool) bson.D {
+	return fieldOp(field, "$exists", exists)
+}
+
+// Regex matches documents where field matches the regular expression pattern using the options (e.g. "i").
+// e.g. Regex("name", "^the ", "i")
+func Regex(field, pattern, options string) bson.D {
+	return fieldOp(field, "$regex", primitive.Regex{Pattern: pattern, Options: options})
+}
+
+// Size matches documents where the array field holds exactly size elements.
+func Size(field string, size int) bson.D {
+	return fieldOp(field, "$size", size)
+}
+
+// ElemMatch matches documents where at least one element of the array field matches every one of the filters.
+// Field names in the filters are relative to the array element.
+// e.g. ElemMatch("encounters", Eq("location", "Arkham"), Gte("year", 2020))
+func ElemMatch(field string, filters ...bson.D) bson.D {
+	return fieldOp(field, "$elemMatch", merge(filters))
+}
+
+// And matches documents which match every one of the filters. A single filter is returned as is, while
+// no filters return an empty filter (which matches every document).
+func And(filters ...bson.D) bson.D {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	return logical("$and", filters)
+}
+
+// Or matches documents which match at least one of the filters. A single filter is returned as is, while
+// no filters return an empty filter (which matches every document).
+func Or(filters ...bson.D) bson.D {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	return logical("$or", filters)
+}
+
+// Nor matches documents which match none of the filters. No filters return an empty filter (which matches every document).
+func Nor(filters ...bson.D) bson.D {
+	return logical("$nor", filters)
+}
+
+// Not matches documents which do not match filter. A filter on a single field using operators (e.g. Gt("age", 5))
+// becomes {"age": {"$not": {"$gt": 5}}}, while any other filter becomes {"$nor": [filter]}.
+func Not(filter bson.D) bson.D {
+	if len(filter) == 1 && !isOperator(filter[0].Key) {
+		if ops, ok := filter[0].Value.(bson.D); ok && isOperatorDoc(ops) {
+			return fieldOp(filter[0].Key, "$not", ops)
+		}
+	}
+	return Nor(filter)
+}
+
+// Expr matches documents for which the aggregation expression is true. It allows fields of the same
+// document to be compared with each other (see ExprEq and friends).
+// e.g. Expr(bson.D{{Key: "$gt", Value: bson.A{"$spent", "$budget"}}})
+func Expr(expression interface{}) bson.D {
+	return bson.D{{Key: "$expr", Value: expression}}
+}
+
+// Field returns the aggregation expression referring to the field at path, for use in Expr.
+// e.g. Field("budget") is "$budget"
+func Field(path string) string {
+	return "$" + path
+}
+
+// ExprEq matches documents where the expressions (typically Field values) are equal.
+// e.g. ExprEq(Field("createdBy"), Field("updatedBy"))
+func ExprEq(left, right interface{}) bson.D {
+	return compare("$eq", left, right)
+}
+
+// ExprNe matches documents where the expressions (typically Field values) are not equal.
+func ExprNe(left, right interface{}) bson.D {
+	return compare("$ne", left, right)
+}
+
+// ExprGt matches documents where the left expression is greater than the right expression.
+// e.g. ExprGt(Field("spent"), Field("budget"))
+func ExprGt(left, right interface{}) bson.D {
+	return compare("$gt", left, right)
+}
+
+// ExprGte matches documents where the left expression is greater than or equal to the right expression.
+func ExprGte(left, right interface{}) bson.D {
+	return compare("$gte", left, right)
+}
+
+// ExprLt matches documents where the left expression is less than the right expression.
+func ExprLt(left, right interface{}) bson.D {
+	return compare("$lt", left, right)
+}
+
+// ExprLte matches documents where the left expression is less than or equal to the right expression.
+func ExprLte(left, right interface{}) bson.D {
+	return compare("$lte", left, right)
+}
+
+// compare returns an $expr comparing left and right using operator
+func compare(operator string, left, right interface{}) bson.D {
+	return Expr(bson.D{{Key: operator, Value: bson.A{left, right}}})
+}
+
+// logical returns a filter combining filters using operator (e.g. $and). The server rejects an empty
+// array, so no filters return an empty filter.
+func logical(operator string, filters []bson.D) bson.D {
+	if len(filters) == 0 {
+		return bson.D{}
+	}
+	clauses := make(bson.A, len(filters))
+	for i, filter := range filters {
+		clauses[i] = filter
+	}
+	return bson.D{{Key: operator, Value: clauses}}
+}
+
+// merge combines filters into a single document. Filters on the same field have their operators merged,
+// so ElemMatch("scores", Gte("value", 80), Lt("value", 90)) holds {"value": {"$gte": 80, "$lt": 90}}.
+func merge(filters []bson.D) bson.D {
+	merged := bson.D{}
+	for _, filter := range filters {
+		for _, elem := range filter {
+			if i := indexOf(merged, elem.Key); i >= 0 {
+				existing, existingOK := merged[i].Value.(bson.D)
+				ops, ok := elem.Value.(bson.D)
+				if existingOK && ok && isOperatorDoc(existing) && isOperatorDoc(ops) {
+					merged[i].Value = append(append(bson.D{}, existing...), ops...)
+					continue
+				}
+			}
+			merged = append(merged, elem)
+		}
+	}
+	return merged
+}
+
+// indexOf returns the position of key in d (or -1 if d does not hold key)
+func indexOf(d bson.D, key string) int {
+	for i, elem := range d {
+		if elem.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// isOperator returns true if key is a query operator (e.g. $gt)
+func isOperator(key string) bool {
+	return len(key) > 0 && key[0] == '$'
+}
+
+// isOperatorDoc returns true if every key of d is a query operator
+func isOperatorDoc(d bson.D) bool {
+	for _, elem := range d {
+		if !isOperator(elem.Key) {
+			return false
+		}
+	}
+	return len(d) > 0
+}
+
+// array returns values as a bson.A. A single slice or array (other than a []byte) is expanded into its elements.
+func array(values []interface{}) bson.A {
+	if len(values) == 1 && values[0] != nil {
+		v := reflect.ValueOf(values[0])
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+			expanded := make(bson.A, v.Len())
+			for i := range expanded {
+				expanded[i] = v.Index(i).Interface()
+			}
+			return expanded
+		}
+	}
+	return bson.A(values)
+}
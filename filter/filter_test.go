@@ -0,0 +1,61 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo/filter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFilter(t *testing.T) {
+	is := assert.New(t)
+	is.Equal(bson.D{{Key: "name", Value: bson.D{{Key: "$eq", Value: "Bane"}}}}, filter.Eq("name", "Bane"))
+	for op, f := range map[string]func(string, interface{}) bson.D{
+		"$ne": filter.Ne, "$gt": filter.Gt, "$gte": filter.Gte, "$lt": filter.Lt, "$lte": filter.Lte,
+	} {
+		is.Equal(bson.D{{Key: "evilness", Value: bson.D{{Key: op, Value: 90}}}}, f("evilness", 90), op)
+	}
+
+	names := []string{"Bane", "Two-Face"}
+	is.Equal(bson.D{{Key: "name", Value: bson.D{{Key: "$in", Value: bson.A{"Bane", "Two-Face"}}}}}, filter.In("name", names),
+		"A single slice should be expanded")
+	is.Equal(filter.In("name", names), filter.In("name", "Bane", "Two-Face"))
+	is.Equal(bson.D{{Key: "name", Value: bson.D{{Key: "$nin", Value: bson.A{"Bane"}}}}}, filter.Nin("name", "Bane"))
+	is.Equal(bson.D{{Key: "tags", Value: bson.D{{Key: "$all", Value: bson.A{"clown", "chemicals"}}}}}, filter.All("tags", "clown", "chemicals"))
+	is.Equal(bson.D{{Key: "notes", Value: bson.D{{Key: "$exists", Value: false}}}}, filter.Exists("notes", false))
+	is.Equal(bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: primitive.Regex{Pattern: "^the ", Options: "i"}}}}},
+		filter.Regex("name", "^the ", "i"))
+	is.Equal(bson.D{{Key: "tags", Value: bson.D{{Key: "$size", Value: 2}}}}, filter.Size("tags", 2))
+
+	is.Equal(bson.D{{Key: "encounters", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+		{Key: "location", Value: bson.D{{Key: "$eq", Value: "Arkham"}}},
+		{Key: "year", Value: bson.D{{Key: "$gte", Value: 2020}, {Key: "$lt", Value: 2022}}},
+	}}}}}, filter.ElemMatch("encounters", filter.Eq("location", "Arkham"), filter.Gte("year", 2020), filter.Lt("year", 2022)),
+		"Operators on the same field should be merged")
+
+	joker, bane := filter.Eq("name", "The Joker"), filter.Eq("name", "Bane")
+	is.Equal(bson.D{{Key: "$and", Value: bson.A{joker, bane}}}, filter.And(joker, bane))
+	is.Equal(bson.D{{Key: "$or", Value: bson.A{joker, bane}}}, filter.Or(joker, bane))
+	is.Equal(bson.D{{Key: "$nor", Value: bson.A{joker, bane}}}, filter.Nor(joker, bane))
+	// The server rejects an empty $and/$or/$nor array
+	is.Equal(bson.D{}, filter.And(), "No filters should match every document")
+	is.Equal(bson.D{}, filter.Or())
+	is.Equal(bson.D{}, filter.Nor())
+	is.Equal(joker, filter.And(joker), "A single filter should be returned as is")
+	is.Equal(joker, filter.Or(joker))
+	is.Equal(bson.D{{Key: "$nor", Value: bson.A{joker}}}, filter.Nor(joker))
+	is.Equal(bson.D{{Key: "evilness", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$gt", Value: 90}}}}}}, filter.Not(filter.Gt("evilness", 90)))
+	is.Equal(bson.D{{Key: "$nor", Value: bson.A{filter.Or(joker, bane)}}}, filter.Not(filter.Or(joker, bane)),
+		"Filters which are not on a single field should be negated using $nor")
+
+	is.Equal(bson.D{{Key: "$expr", Value: bson.D{{Key: "$gt", Value: bson.A{"$spent", "$budget"}}}}},
+		filter.ExprGt(filter.Field("spent"), filter.Field("budget")))
+	is.Equal(bson.D{{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$createdBy", "$updatedBy"}}}}},
+		filter.ExprEq(filter.Field("createdBy"), filter.Field("updatedBy")))
+
+	// Filters must still marshal once composed
+	_, err := bson.Marshal(filter.And(filter.In("name", names), filter.Not(filter.Regex("name", "^the", ""))))
+	is.NoError(err)
+}
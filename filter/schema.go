@@ -0,0 +1,300 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrUnknownField is returned by Schema.Validate when a filter refers to a field the model does not have
+var ErrUnknownField = errors.New("unknown field")
+
+// Schema checks the field names used by a filter against the bson tags of a struct, so a typo fails fast
+// rather than silently matching nothing. Fields are named the way the driver names them: by their bson tag,
+// or their lowercased Go name if there is no tag. Inline fields are flattened.
+// Maps, interface{} values and bson documents (e.g. bson.M) accept any sub-field.
+// e.g. enemies := filter.MustSchema(Enemy{}); err := enemies.Validate(filter.Eq("nmae", "Bane")) // ErrUnknownField
+type Schema struct {
+	model reflect.Type
+}
+
+// NewSchema returns a Schema checking filters against the struct (or pointer to a struct) model
+func NewSchema(model interface{}) (*Schema, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("a schema can only be created from a struct - received %T", model)
+	}
+	return &Schema{model: t}, nil
+}
+
+// MustSchema is like NewSchema but panics if model is not a struct. It simplifies declaring schemas as globals.
+// e.g. var enemySchema = filter.MustSchema(Enemy{})
+func MustSchema(model interface{}) *Schema {
+	s, err := NewSchema(model)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate returns an error wrapping ErrUnknownField should filter (typically a bson.D or bson.M)
+// refer to a field the model does not have. Fields compared using Expr are validated as well.
+func (s *Schema) Validate(filter interface{}) error {
+	return s.validateDoc(s.model, filter, "")
+}
+
+// Must returns filter, panicking should it refer to a field the model does not have (see Validate).
+// e.g. coll.Find(enemySchema.Must(filter.Eq("name", "Bane"))).One(&enemy)
+func (s *Schema) Must(filter bson.D) bson.D {
+	if err := s.Validate(filter); err != nil {
+		panic(err)
+	}
+	return filter
+}
+
+// validateDoc validates the fields of the filter doc, which apply to values of type t.
+// prefix is the path of doc within the model, for error messages.
+func (s *Schema) validateDoc(t reflect.Type, doc interface{}, prefix string) error {
+	for _, elem := range elements(doc) {
+		switch elem.Key {
+		case "$and", "$or", "$nor":
+			for _, clause := range values(elem.Value) {
+				if err := s.validateDoc(t, clause, prefix); err != nil {
+					return err
+				}
+			}
+			continue
+		case "$expr":
+			if err := s.validateExpr(elem.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		if isOperator(elem.Key) {
+			// e.g. $text, $where or the operators of an $elemMatch on an array of values
+			continue
+		}
+		path := prefix + elem.Key
+		fieldType, err := resolve(t, elem.Key)
+		if err != nil {
+			return s.unknownField(path)
+		}
+		if err = s.validateOperators(fieldType, elem.Value, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOperators validates the filters nested within the operators applied to a field of type t
+// (i.e. $elemMatch and $not)
+func (s *Schema) validateOperators(t reflect.Type, value interface{}, path string) error {
+	if t == nil {
+		// The field accepts anything
+		return nil
+	}
+	for _, op := range elements(value) {
+		switch op.Key {
+		case "$elemMatch":
+			if err := s.validateDoc(elemType(t), op.Value, path+"."); err != nil {
+				return err
+			}
+		case "$not":
+			if err := s.validateOperators(t, op.Value, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateExpr validates every field path (e.g. "$budget") referred to by an aggregation expression
+func (s *Schema) validateExpr(expression interface{}) error {
+	switch expr := expression.(type) {
+	case string:
+		if !strings.HasPrefix(expr, "$") || strings.HasPrefix(expr, "$$") {
+			// A literal or a variable (e.g. $$NOW)
+			return nil
+		}
+		if _, err := resolve(s.model, expr[1:]); err != nil {
+			return s.unknownField(expr[1:])
+		}
+		return nil
+	case bson.D, bson.M, map[string]interface{}:
+		for _, elem := range elements(expr) {
+			if err := s.validateExpr(elem.Value); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, v := range values(expr) {
+			if err := s.validateExpr(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unknownField returns the error describing an unknown field at path
+func (s *Schema) unknownField(path string) error {
+	return fmt.Errorf("%w '%s' - %s has no such bson field", ErrUnknownField, path, s.model)
+}
+
+var (
+	tD   = reflect.TypeOf(primitive.D{})
+	tRaw = reflect.TypeOf(bson.Raw{})
+)
+
+// resolve returns the type of the field at the dotted path within values of type t.
+// A nil type is returned if the field accepts anything (e.g. it is within a map).
+func resolve(t reflect.Type, path string) (reflect.Type, error) {
+	segments := strings.Split(path, ".")
+	for i := 0; i < len(segments); i++ {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() == reflect.Interface || t.Kind() == reflect.Map || t == tD || t == tRaw {
+			return nil, nil
+		}
+		segment := segments[i]
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() == reflect.Uint8 {
+				// Binary data (e.g. an ObjectID) has no fields
+				return nil, ErrUnknownField
+			}
+			t = t.Elem()
+			if !isArrayIndex(segment) {
+				// Fields of array elements may be referred to without an index (e.g. "encounters.location")
+				i--
+			}
+		case reflect.Struct:
+			fieldType, ok := structField(t, segment)
+			if !ok {
+				return nil, ErrUnknownField
+			}
+			t = fieldType
+		default:
+			return nil, ErrUnknownField
+		}
+	}
+	return t, nil
+}
+
+// structField returns the type of the field of struct t which is stored under key.
+// A nil type is returned if the field accepts anything (i.e. it is an inline map).
+func structField(t reflect.Type, key string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			// Unexported fields are not stored
+			continue
+		}
+		name, inline, skip := parseTag(sf)
+		switch {
+		case skip:
+			continue
+		case inline:
+			inlineType := sf.Type
+			if inlineType.Kind() == reflect.Ptr {
+				inlineType = inlineType.Elem()
+			}
+			if inlineType.Kind() == reflect.Map {
+				return nil, true
+			}
+			if fieldType, ok := structField(inlineType, key); ok {
+				return fieldType, true
+			}
+		case name == key:
+			return sf.Type, true
+		}
+	}
+	return nil, false
+}
+
+// parseTag returns the key a struct field is stored under, following the driver's default struct tag parser
+func parseTag(sf reflect.StructField) (name string, inline, skip bool) {
+	tag, ok := sf.Tag.Lookup("bson")
+	if !ok && !strings.Contains(string(sf.Tag), ":") && len(sf.Tag) > 0 {
+		tag = string(sf.Tag)
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	name = strings.ToLower(sf.Name)
+	for i, part := range strings.Split(tag, ",") {
+		if i == 0 && part != "" {
+			name = part
+		}
+		if part == "inline" {
+			inline = true
+		}
+	}
+	return name, inline, false
+}
+
+// isArrayIndex returns true if segment refers to array elements (e.g. "0", "$" or "$[]")
+func isArrayIndex(segment string) bool {
+	if segment == "$" || strings.HasPrefix(segment, "$[") {
+		return true
+	}
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
+
+// elemType returns the type of the elements of the array type t (or nil if t is not an array)
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		return t.Elem()
+	}
+	return nil
+}
+
+// elements returns the elements of a document. Maps are sorted by key, so errors are reported consistently.
+func elements(doc interface{}) bson.D {
+	switch d := doc.(type) {
+	case bson.D:
+		return d
+	case bson.M:
+		return sortedElements(d)
+	case map[string]interface{}:
+		return sortedElements(d)
+	}
+	return nil
+}
+
+// sortedElements returns the elements of m sorted by key
+func sortedElements(m map[string]interface{}) bson.D {
+	d := make(bson.D, 0, len(m))
+	for key, value := range m {
+		d = append(d, bson.E{Key: key, Value: value})
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i].Key < d[j].Key })
+	return d
+}
+
+// values returns the elements of an array (e.g. a bson.A or a []bson.D)
+func values(array interface{}) []interface{} {
+	v := reflect.ValueOf(array)
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type() == tD {
+		return nil
+	}
+	out := make([]interface{}, v.Len())
+	for i := range out {
+		out[i] = v.Index(i).Interface()
+	}
+	return out
+}
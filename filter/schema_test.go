@@ -0,0 +1,84 @@
+package filter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo/filter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type encounter struct {
+	Location string    `bson:"location"`
+	Year     int       `bson:"year"`
+	When     time.Time `bson:"when"`
+}
+
+type audit struct {
+	CreatedBy string `bson:"createdBy"`
+	UpdatedBy string `bson:"updatedBy"`
+}
+
+type enemy struct {
+	ID         primitive.ObjectID     `bson:"_id"`
+	Name       string                 `bson:"name"`
+	Evilness   float64                `bson:"evilness,omitempty"`
+	Tags       []string               `bson:"tags"`
+	Encounters []*encounter           `bson:"encounters"`
+	Attributes map[string]interface{} `bson:"attributes"`
+	Audit      audit                  `bson:",inline"`
+	Secret     string                 `bson:"-"`
+	Untagged   int
+	notStored  string
+}
+
+func TestSchema(t *testing.T) {
+	is := assert.New(t)
+	schema := filter.MustSchema(&enemy{})
+	for _, valid := range []interface{}{
+		filter.Eq("name", "Bane"),
+		filter.And(filter.Eq("_id", primitive.NewObjectID()), filter.Or(filter.Gt("evilness", 9), filter.Size("tags", 2))),
+		filter.ElemMatch("encounters", filter.Eq("location", "Arkham"), filter.Gte("year", 2020)),
+		filter.ElemMatch("tags", bson.D{{Key: "$eq", Value: "clown"}}),
+		filter.Eq("encounters.location", "Arkham"),
+		filter.Eq("encounters.0.when", time.Now()),
+		filter.Eq("attributes.anything.goes", 1),
+		filter.Eq("createdBy", "Alfred"),
+		filter.Eq("untagged", 1),
+		filter.Not(filter.Gt("evilness", 5)),
+		filter.ExprEq(filter.Field("createdBy"), filter.Field("updatedBy")),
+		filter.Expr(bson.D{{Key: "$gt", Value: bson.A{"$$NOW", "$encounters.when"}}}),
+		bson.M{"name": "Bane", "$or": []bson.M{{"evilness": 1}, {"tags": "clown"}}},
+	} {
+		is.NoError(schema.Validate(valid), "%v should be valid", valid)
+	}
+
+	for path, invalid := range map[string]interface{}{
+		"nmae":                 filter.Eq("nmae", "Bane"),
+		"Name":                 filter.Eq("Name", "Bane"),
+		"secret":               filter.Eq("secret", "Batman"),
+		"notstored":            filter.Eq("notstored", "x"),
+		"audit":                filter.Eq("audit", "x"),
+		"name.first":           filter.Eq("name.first", "x"),
+		"_id.x":                filter.Eq("_id.x", "x"),
+		"encounters.locaton":   filter.ElemMatch("encounters", filter.Eq("locaton", "Arkham")),
+		"encounters.0.year.x":  filter.Not(filter.Eq("encounters.0.year.x", 1)),
+		"evilnes":              filter.Or(filter.Eq("name", "Bane"), filter.And(filter.Gt("evilnes", 1))),
+		"budget":               filter.ExprGt(filter.Field("evilness"), filter.Field("budget")),
+		"encounters.when.year": bson.M{"encounters.when.year": 1},
+	} {
+		err := schema.Validate(invalid)
+		is.True(errors.Is(err, filter.ErrUnknownField), "%s should be an unknown field", path)
+		if err != nil {
+			is.Contains(err.Error(), "'"+path+"'")
+		}
+	}
+
+	is.Panics(func() { schema.Must(filter.Eq("nmae", "Bane")) })
+	is.Equal(filter.Eq("name", "Bane"), schema.Must(filter.Eq("name", "Bane")))
+	_, err := filter.NewSchema("not a struct")
+	is.Error(err)
+}
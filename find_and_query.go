@@ -1,10 +1,14 @@
 package easymongo
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // FindAndQuery is used for Find().OneAnd() operations (e.g. Find().OneAnd().Replace())
@@ -116,6 +120,46 @@ func (q *FindAndQuery) Timeout(d time.Duration) *FindAndQuery {
 	return q
 }
 
+// WithContext will consume the supplied context. A note that this is completely optional
+// as Timeout will auto-create a context for you if one is not supplied.
+// This is typically useful when using a context across many functions (e.g. to propagate a trace).
+func (q *FindAndQuery) WithContext(ctx context.Context) *FindAndQuery {
+	q.Query.setContext(&ctx)
+	return q
+}
+
+// Retry overrides the connection's RetryPolicy for this query. Use RetryPolicy{MaxAttempts: 1} to disable retrying.
+func (q *FindAndQuery) Retry(policy RetryPolicy) *FindAndQuery {
+	q.Query.setRetry(policy)
+	return q
+}
+
+// ReadPreference overrides the read preference for this query only.
+// e.g. ReadPreference(readpref.SecondaryPreferred())
+func (q *FindAndQuery) ReadPreference(rp *readpref.ReadPref) *FindAndQuery {
+	q.Query.setReadPreference(rp)
+	return q
+}
+
+// ReadConcern overrides the read concern for this query only.
+func (q *FindAndQuery) ReadConcern(rc *readconcern.ReadConcern) *FindAndQuery {
+	q.Query.setReadConcern(rc)
+	return q
+}
+
+// WriteConcern overrides the write concern for this query only.
+func (q *FindAndQuery) WriteConcern(wc *writeconcern.WriteConcern) *FindAndQuery {
+	q.Query.setWriteConcern(wc)
+	return q
+}
+
+// Flags overrides the read concern, read preference and write concern set in flags for this query only.
+// e.g. Flags(ReadPreferenceSecondaryPreferred | ReadConcernMajority)
+func (q *FindAndQuery) Flags(flags ConnectionFlag) *FindAndQuery {
+	q.Query.setFlags(flags)
+	return q
+}
+
 // ReturnDocumentAfterModification specifies the object should be returned after modification is complete.
 // By default, the document is returned before the query.
 func (q *FindAndQuery) ReturnDocumentAfterModification() *FindAndQuery {
@@ -164,27 +208,23 @@ func (q *FindAndQuery) findOneAndReplaceOptions() *options.FindOneAndReplaceOpti
 
 // Update ends up running `findAndModify()` to update (and return) the first matching document
 // If you do not need the result object, consider running `collection.UpdateOne()` instead.
-// mongo.ErrNoDocuments is returned in the case that nothing matches the specified query.
+// ErrNoDocuments (which also matches mongo.ErrNoDocuments) is returned in the case that nothing matches the specified query.
 func (q *FindAndQuery) Update(updateQuery interface{}) (err error) {
-	mongoColl := q.collection.mongoColl
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
 	opts := q.findOneAndUpdateOptions()
-	err = mongoColl.FindOneAndUpdate(ctx, q.filter, updateQuery, opts).Decode(q.result)
-	return err
+	return q.run(opFindAndUpdate, func(ctx context.Context) error {
+		return q.collection.mongoCollection().FindOneAndUpdate(ctx, q.filter, updateQuery, opts).Decode(q.result)
+	})
 }
 
 // Replace ultimately ends up running `findOneAndReplace()`. If you do not need the existing
 // value/object, it is recommended to instead run `collection.Replace().Execute()`
-// mongo.ErrNoDocuments is returned in the case that nothing matches the specified query.
+// ErrNoDocuments (which also matches mongo.ErrNoDocuments) is returned in the case that nothing matches the specified query.
 func (q *FindAndQuery) Replace(replacementObject interface{}) (err error) {
-	mongoColl := q.collection.mongoColl
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
 	opts := q.findOneAndReplaceOptions()
-	res := mongoColl.FindOneAndReplace(ctx, q.filter, replacementObject, opts)
-
-	return res.Decode(q.result)
+	return q.run(opFindAndReplace, func(ctx context.Context) error {
+		res := q.collection.mongoCollection().FindOneAndReplace(ctx, q.filter, replacementObject, opts)
+		return res.Decode(q.result)
+	})
 }
 
 func (q *FindAndQuery) findOneAndDeleteOptions() *options.FindOneAndDeleteOptions {
@@ -204,16 +244,14 @@ func (q *FindAndQuery) findOneAndDeleteOptions() *options.FindOneAndDeleteOption
 
 // Delete ultimately ends up running `findOneAndDelete()`. If you do not need the existing
 // value/object prior to the deletion, it is recommended to instead run `Collection().Delete().One()`
-// mongo.ErrNoDocuments is returned in the case that nothing matches the specified query.
+// ErrNoDocuments (which also matches mongo.ErrNoDocuments) is returned in the case that nothing matches the specified query.
 func (q *FindAndQuery) Delete() (err error) {
 	if q.returnDocument == options.After {
 		// Explicitly fail if the user is attempting to get the document after it was deleted
 		return fmt.Errorf("options.After is not compatible with FindAnd().Delete() as the document will always not be found after deletion")
 	}
-	mongoColl := q.collection.mongoColl
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
 	opts := q.findOneAndDeleteOptions()
-	err = mongoColl.FindOneAndDelete(ctx, q.filter, opts).Decode(q.result)
-	return err
+	return q.run(opFindAndDelete, func(ctx context.Context) error {
+		return q.collection.mongoCollection().FindOneAndDelete(ctx, q.filter, opts).Decode(q.result)
+	})
 }
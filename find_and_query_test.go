@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -74,7 +75,14 @@ func TestFindAnd(t *testing.T) {
 		is.Equal("The Riddler", enemyBefore.Name, "The document doesn't appear to be properly populated")
 
 		err = coll.Find(filter).One(&enemyAfter)
-		is.Equal(mongo.ErrNoDocuments, err, "The document shouldn't exist after the deletion")
+		is.ErrorIs(err, mongo.ErrNoDocuments, "The document shouldn't exist after the deletion")
+		is.ErrorIs(err, easymongo.ErrNoDocuments, "The document shouldn't exist after the deletion")
+
+		err = coll.Find(filter).OneAnd(&enemyAfter).Delete()
+		is.ErrorIs(err, easymongo.ErrNoDocuments, "FindAnd should return ErrNoDocuments when nothing matches")
+		is.ErrorIs(err, mongo.ErrNoDocuments)
+		err = coll.Find(filter).OneAnd(&enemyAfter).Update(bson.M{"$set": bson.M{"deceased": true}})
+		is.ErrorIs(err, easymongo.ErrNoDocuments, "FindAnd should return ErrNoDocuments when nothing matches")
 		is.True(enemyAfter.ID.IsZero(), "The ID should be empty after deletion")
 		is.Nil(enemyAfter.LastEncounter, "The update to the document doesn't appear to be working")
 	})
@@ -1,6 +1,7 @@
 package easymongo
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"time"
@@ -81,17 +82,17 @@ type FindQuery struct {
 func (q *FindQuery) All(results interface{}) error {
 	// TODO: Check kind to make sure results is a slice or map
 	opts := q.findOptions()
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
-	cursor, err := q.collection.mongoColl.Find(ctx, q.filter, opts)
-	if err != nil {
-		return err
-	}
+	return q.run(opFindAll, func(ctx context.Context) error {
+		cursor, err := q.collection.mongoCollection().Find(ctx, q.filter, opts)
+		if err != nil {
+			return err
+		}
 
-	// TODO: Inject ErrNotFound if option specified
-	err = cursor.All(ctx, results)
-	err = q.collection.handleErr(err)
-	return err
+		// TODO: Inject ErrNotFound if option specified
+		err = cursor.All(ctx, results)
+		err = q.collection.handleErr(err)
+		return err
+	})
 }
 
 // findOneOptions generates the native mongo driver FindOneOptions from the FindQuery
@@ -121,9 +122,9 @@ func (q *FindQuery) One(result interface{}) (err error) {
 		return ErrPointerRequired
 	}
 	opts := q.findOneOptions()
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
-	err = q.collection.mongoColl.FindOne(ctx, q.filter, opts).Decode(result)
+	err = q.run(opFindOne, func(ctx context.Context) error {
+		return q.collection.mongoCollection().FindOne(ctx, q.filter, opts).Decode(result)
+	})
 	err = q.collection.handleErr(err)
 	if err != nil {
 		return err
@@ -168,13 +169,15 @@ func (q *FindQuery) findOptions() *options.FindOptions {
 
 // Cursor results the mongo.Cursor. This is useful when working with large numbers of results.
 // Alternatively, consider calling collection.Find().One() or collection.Find().All().
-func (q *FindQuery) Cursor() (*mongo.Cursor, error) {
+func (q *FindQuery) Cursor() (cursor *mongo.Cursor, err error) {
 	// TODO: Check kind ot make sure it's a slice or map
 	opts := q.findOptions()
 
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
-	return q.collection.mongoColl.Find(ctx, q.filter, opts)
+	err = q.run(opFindCursor, func(ctx context.Context) error {
+		cursor, err = q.collection.mongoCollection().Find(ctx, q.filter, opts)
+		return err
+	})
+	return cursor, err
 }
 
 // countOptions generates the native mongo driver CountOptions from the FindQuery
@@ -193,11 +196,12 @@ func (q *FindQuery) countOptions() *options.CountOptions {
 
 // Count counts the number of documents using the specified query
 func (q *FindQuery) Count() (int, error) {
+	var count int64
 	opts := q.countOptions()
-	mongoColl := q.collection.mongoColl
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
-	count, err := mongoColl.CountDocuments(ctx, q.filter, opts)
+	err := q.run(opFindCount, func(ctx context.Context) (err error) {
+		count, err = q.collection.mongoCollection().CountDocuments(ctx, q.filter, opts)
+		return err
+	})
 	err = q.collection.handleErr(err)
 	return int(count), err
 }
@@ -215,7 +219,7 @@ func (q *FindQuery) findDistinctOptions() *options.DistinctOptions {
 // Sort/Limit/Skip are presently ignored.
 func (q *FindQuery) Distinct(fieldName string) (interfaceSlice []interface{}, err error) {
 	// opts := q.findDistinctOptions()
-	// mongoColl := q.collection.mongoColl
+	// mongoColl := q.collection.mongoCollection()
 	// ctx, cancelFunc := q.getContext()
 	// defer cancelFunc()
 	// interfaceSlice, err = mongoColl.Distinct(ctx, fieldName, q.filter, opts)
@@ -269,7 +273,9 @@ func (q *FindQuery) Distinct(fieldName string) (interfaceSlice []interface{}, er
 	// { $replaceRoot: { newRoot: { $ifNull: [ "$name", { _id: "$_id", missingName: true} ] } } }
 	// distinctDocuments := []distinctDocument{}
 	d := distinctDocument{}
-	err = q.collection.Aggregate(pipeline).One(&d)
+	err = q.run(opFindDistinct, func(ctx context.Context) error {
+		return q.collection.Aggregate(pipeline).one(ctx, &d)
+	})
 	// if err != nil {
 	// 	return interfaceSlice, err
 	// } else if len(distinctDocuments) == 1 && len(distinctDocuments[0].distinctValues) > 0 {
@@ -337,11 +343,12 @@ func (q *FindQuery) DistinctStrings(fieldName string) (stringSlice []string, err
 	// if err != nil {
 	// 	return stringSlice, err
 	// }
+	var iSlice []interface{}
 	opts := q.findDistinctOptions()
-	mongoColl := q.collection.mongoColl
-	ctx, cancelFunc := q.getContext()
-	defer cancelFunc()
-	iSlice, err := mongoColl.Distinct(ctx, fieldName, q.filter, opts)
+	err = q.run(opFindDistinctStrings, func(ctx context.Context) (err error) {
+		iSlice, err = q.collection.mongoCollection().Distinct(ctx, fieldName, q.filter, opts)
+		return err
+	})
 	if err = q.collection.handleErr(err); err != nil {
 		return stringSlice, err
 	}
@@ -1,9 +1,13 @@
 package easymongo
 
 import (
+	"context"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // AllowDiskUse sets a flag which allows queries to page to disk space
@@ -111,3 +115,43 @@ func (q *FindQuery) Timeout(d time.Duration) *FindQuery {
 	q.Query.setTimeout(d)
 	return q
 }
+
+// WithContext will consume the supplied context. A note that this is completely optional
+// as Timeout will auto-create a context for you if one is not supplied.
+// This is typically useful when using a context across many functions (e.g. to propagate a trace).
+func (q *FindQuery) WithContext(ctx context.Context) *FindQuery {
+	q.Query.setContext(&ctx)
+	return q
+}
+
+// Retry overrides the connection's RetryPolicy for this query. Use RetryPolicy{MaxAttempts: 1} to disable retrying.
+func (q *FindQuery) Retry(policy RetryPolicy) *FindQuery {
+	q.Query.setRetry(policy)
+	return q
+}
+
+// ReadPreference overrides the read preference for this query only.
+// e.g. ReadPreference(readpref.SecondaryPreferred())
+func (q *FindQuery) ReadPreference(rp *readpref.ReadPref) *FindQuery {
+	q.Query.setReadPreference(rp)
+	return q
+}
+
+// ReadConcern overrides the read concern for this query only.
+func (q *FindQuery) ReadConcern(rc *readconcern.ReadConcern) *FindQuery {
+	q.Query.setReadConcern(rc)
+	return q
+}
+
+// WriteConcern overrides the write concern for this query only.
+func (q *FindQuery) WriteConcern(wc *writeconcern.WriteConcern) *FindQuery {
+	q.Query.setWriteConcern(wc)
+	return q
+}
+
+// Flags overrides the read concern, read preference and write concern set in flags for this query only.
+// e.g. Flags(ReadPreferenceSecondaryPreferred | ReadConcernMajority)
+func (q *FindQuery) Flags(flags ConnectionFlag) *FindQuery {
+	q.Query.setFlags(flags)
+	return q
+}
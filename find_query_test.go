@@ -1,11 +1,17 @@
 package easymongo_test
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 func TestFind(t *testing.T) {
@@ -20,6 +26,10 @@ func TestFind(t *testing.T) {
 		err := coll.Find(bson.M{"name": expectedName}).One(&e)
 		is.NoError(err, "Couldn't Find.One() the name '%s'", expectedName)
 		is.Equal(expectedName, e.Name, "Returned object appears unpopulated")
+
+		err = coll.Find(bson.M{"name": "Mr. Nobody"}).One(&e)
+		is.ErrorIs(err, easymongo.ErrNoDocuments, "A filter matching nothing should return ErrNoDocuments")
+		is.ErrorIs(err, mongo.ErrNoDocuments, "ErrNoDocuments should still match mongo.ErrNoDocuments")
 	})
 	t.Run("Find().Many()", func(t *testing.T) {
 		is := assert.New(t)
@@ -40,4 +50,35 @@ func TestFind(t *testing.T) {
 		is.NoError(err, "Failed to Find.All() for all documents in collection", expectedName)
 		is.GreaterOrEqual(len(enemies), 5, "There should be at least 5 documents in the test collection")
 	})
+	t.Run("Find() with a per-query read concern", func(t *testing.T) {
+		is := assert.New(t)
+		var lock sync.Mutex
+		var commands []bson.D
+		remove := conn.AddObserver(easymongo.CommandObserverFuncs{
+			OnStarted: func(_ context.Context, e *easymongo.CommandStartedEvent) {
+				lock.Lock()
+				defer lock.Unlock()
+				if e.CommandName == "find" {
+					commands = append(commands, e.Command)
+				}
+			},
+		})
+		defer remove()
+
+		var e enemy
+		err := coll.Find(bson.M{"name": "The Joker"}).
+			ReadConcern(readconcern.Majority()).
+			ReadPreference(readpref.PrimaryPreferred()).One(&e)
+		is.NoError(err)
+		is.NoError(coll.Find(bson.M{"name": "The Joker"}).One(&e))
+
+		lock.Lock()
+		defer lock.Unlock()
+		if is.Len(commands, 2) {
+			rc, _ := commands[0].Map()["readConcern"].(bson.D)
+			is.Equal("majority", rc.Map()["level"], "The read concern should be sent with the query")
+			rc, _ = commands[1].Map()["readConcern"].(bson.D)
+			is.NotEqual("majority", rc.Map()["level"], "The override should not leak into the collection")
+		}
+	})
 }
@@ -0,0 +1,200 @@
+package easymongo
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// flagSetting maps a single ConnectionFlag to its value in the "key=value" form used by
+// ParseConnectionFlags and String()
+type flagSetting struct {
+	flag  ConnectionFlag
+	value string
+}
+
+// flagGroup holds the mutually exclusive flags which configure a single setting
+type flagGroup struct {
+	key      string
+	settings []flagSetting
+}
+
+// flagGroups lists every setting a ConnectionFlag can hold, in the order String() renders them
+var flagGroups = []flagGroup{
+	{key: "readConcern", settings: []flagSetting{
+		{ReadConcernAvailable, "available"},
+		{ReadConcernLinearizable, "linearizable"},
+		{ReadConcernLocal, "local"},
+		{ReadConcernMajority, "majority"},
+		{ReadConcernSnapshot, "snapshot"},
+	}},
+	{key: "readPreference", settings: []flagSetting{
+		{ReadPreferenceNearest, "nearest"},
+		{ReadPreferencePrimary, "primary"},
+		{ReadPreferencePrimaryPreferred, "primaryPreferred"},
+		{ReadPreferenceSecondary, "secondary"},
+		{ReadPreferenceSecondaryPreferred, "secondaryPreferred"},
+	}},
+	{key: "w", settings: []flagSetting{
+		{WriteConcernW1, "1"},
+		{WriteConcernW2, "2"},
+		{WriteConcernW3, "3"},
+		{WriteConcernMajority, "majority"},
+	}},
+	{key: "j", settings: []flagSetting{
+		{WriteConcernJournal, "true"},
+	}},
+}
+
+// flagNames holds the Go name of each flag for error messages
+var flagNames = map[ConnectionFlag]string{
+	ReadConcernAvailable:             "ReadConcernAvailable",
+	ReadConcernLinearizable:          "ReadConcernLinearizable",
+	ReadConcernLocal:                 "ReadConcernLocal",
+	ReadConcernMajority:              "ReadConcernMajority",
+	ReadConcernSnapshot:              "ReadConcernSnapshot",
+	ReadPreferenceNearest:            "ReadPreferenceNearest",
+	ReadPreferencePrimary:            "ReadPreferencePrimary",
+	ReadPreferencePrimaryPreferred:   "ReadPreferencePrimaryPreferred",
+	ReadPreferenceSecondary:          "ReadPreferenceSecondary",
+	ReadPreferenceSecondaryPreferred: "ReadPreferenceSecondaryPreferred",
+	WriteConcernW1:                   "WriteConcernW1",
+	WriteConcernW2:                   "WriteConcernW2",
+	WriteConcernW3:                   "WriteConcernW3",
+	WriteConcernMajority:             "WriteConcernMajority",
+}
+
+// Validate returns an error if the flags contradict each other (e.g. ReadPreferencePrimary|ReadPreferenceSecondary)
+// or include ReadPreferenceWriteConcern, which does not map to any read preference.
+func (connectFlag ConnectionFlag) Validate() error {
+	if connectFlag&ReadPreferenceWriteConcern != 0 {
+		return fmt.Errorf("ReadPreferenceWriteConcern is not a valid read preference")
+	}
+	for _, group := range flagGroups {
+		var set []string
+		for _, setting := range group.settings {
+			if connectFlag&setting.flag != 0 {
+				set = append(set, flagNames[setting.flag])
+			}
+		}
+		if len(set) > 1 {
+			return fmt.Errorf("the connection flags %s conflict - only one %s may be set", strings.Join(set, ", "), group.key)
+		}
+	}
+	return nil
+}
+
+// String renders the flags in the form accepted by ParseConnectionFlags
+// e.g. "readConcern=majority,readPreference=nearest,w=majority"
+// ReadPreferenceWriteConcern has no key=value form, so it is left out. The output parses back to the same
+// flags only when Validate passes - conflicting flags are all rendered, and ParseConnectionFlags rejects them.
+func (connectFlag ConnectionFlag) String() string {
+	var parts []string
+	for _, group := range flagGroups {
+		for _, setting := range group.settings {
+			if connectFlag&setting.flag != 0 {
+				parts = append(parts, group.key+"="+setting.value)
+			}
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseConnectionFlags parses a comma separated list of key=value settings, so flags can be set from configuration.
+// The keys are readConcern (available, linearizable, local, majority or snapshot), readPreference (nearest, primary,
+// primaryPreferred, secondary or secondaryPreferred), w (1, 2, 3 or majority) and j (true or false).
+// Keys and values are case insensitive. An error is returned for unknown settings or conflicting flags.
+// e.g. ParseConnectionFlags("readConcern=majority,readPreference=nearest,w=majority,j=true")
+func ParseConnectionFlags(s string) (ConnectionFlag, error) {
+	var flags ConnectionFlag
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return 0, fmt.Errorf("the connection flag '%s' is not in the form key=value", part)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		flag, err := parseConnectionFlag(key, value)
+		if err != nil {
+			return 0, err
+		}
+		flags |= flag
+	}
+	if err := flags.Validate(); err != nil {
+		return 0, err
+	}
+	return flags, nil
+}
+
+// parseConnectionFlag returns the flag matching a single key=value setting
+func parseConnectionFlag(key, value string) (ConnectionFlag, error) {
+	if strings.EqualFold(key, "j") && strings.EqualFold(value, "false") {
+		return 0, nil
+	}
+	for _, group := range flagGroups {
+		if !strings.EqualFold(group.key, key) {
+			continue
+		}
+		for _, setting := range group.settings {
+			if strings.EqualFold(setting.value, value) {
+				return setting.flag, nil
+			}
+		}
+		return 0, fmt.Errorf("'%s' is not a valid value for the connection flag '%s'", value, key)
+	}
+	return 0, fmt.Errorf("'%s' is not a known connection flag", key)
+}
+
+// concerns returns the read concern, read preference and write concern set in the flags. Each is nil if it is not set.
+// Should flags conflict (see Validate), the first flag of each setting in the order listed by String() wins.
+func (connectFlag ConnectionFlag) concerns() (rc *readconcern.ReadConcern, rp *readpref.ReadPref, wc *writeconcern.WriteConcern) {
+	switch {
+	case connectFlag&ReadConcernAvailable != 0:
+		rc = readconcern.Available()
+	case connectFlag&ReadConcernLinearizable != 0:
+		rc = readconcern.Linearizable()
+	case connectFlag&ReadConcernLocal != 0:
+		rc = readconcern.Local()
+	case connectFlag&ReadConcernMajority != 0:
+		rc = readconcern.Majority()
+	case connectFlag&ReadConcernSnapshot != 0:
+		rc = readconcern.Snapshot()
+	}
+	switch {
+	case connectFlag&ReadPreferenceNearest != 0:
+		rp = readpref.Nearest()
+	case connectFlag&ReadPreferencePrimary != 0:
+		rp = readpref.Primary()
+	case connectFlag&ReadPreferencePrimaryPreferred != 0:
+		rp = readpref.PrimaryPreferred()
+	case connectFlag&ReadPreferenceSecondary != 0:
+		rp = readpref.Secondary()
+	case connectFlag&ReadPreferenceSecondaryPreferred != 0:
+		rp = readpref.SecondaryPreferred()
+	}
+
+	wOpts := make([]writeconcern.Option, 0)
+	if connectFlag&WriteConcernJournal != 0 {
+		wOpts = append(wOpts, writeconcern.J(true))
+	}
+	switch {
+	case connectFlag&WriteConcernW1 != 0:
+		wOpts = append(wOpts, writeconcern.W(1))
+	case connectFlag&WriteConcernW2 != 0:
+		wOpts = append(wOpts, writeconcern.W(2))
+	case connectFlag&WriteConcernW3 != 0:
+		wOpts = append(wOpts, writeconcern.W(3))
+	case connectFlag&WriteConcernMajority != 0:
+		wOpts = append(wOpts, writeconcern.WMajority())
+	}
+	if len(wOpts) != 0 {
+		wc = writeconcern.New(wOpts...)
+	}
+	return rc, rp, wc
+}
@@ -0,0 +1,68 @@
+package easymongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestConnectionFlags(t *testing.T) {
+	is := assert.New(t)
+	flags, err := easymongo.ParseConnectionFlags("readConcern=majority, readPreference=nearest,w=majority,j=true")
+	is.NoError(err)
+	is.Equal(easymongo.ReadConcernMajority|easymongo.ReadPreferenceNearest|easymongo.WriteConcernMajority|easymongo.WriteConcernJournal, flags)
+	is.Equal("readConcern=majority,readPreference=nearest,w=majority,j=true", flags.String())
+
+	roundTrip, err := easymongo.ParseConnectionFlags(easymongo.DefaultPrimary.String())
+	is.NoError(err)
+	is.Equal(easymongo.DefaultPrimary, roundTrip)
+
+	flags = easymongo.ReadPreferenceWriteConcern | easymongo.ReadConcernLocal
+	is.Equal("readConcern=local", flags.String(), "ReadPreferenceWriteConcern has no key=value form")
+	roundTrip, err = easymongo.ParseConnectionFlags(flags.String())
+	is.NoError(err, "String() should always produce parseable output")
+	is.Equal(easymongo.ReadConcernLocal, roundTrip)
+
+	flags, err = easymongo.ParseConnectionFlags("READPREFERENCE=SecondaryPreferred,w=1,j=false")
+	is.NoError(err, "Keys and values should be case insensitive")
+	is.Equal(easymongo.ReadPreferenceSecondaryPreferred|easymongo.WriteConcernW1, flags)
+
+	flags, err = easymongo.ParseConnectionFlags("")
+	is.NoError(err)
+	is.Zero(flags)
+
+	for _, invalid := range []string{"w=4", "readConcern", "consistency=strong", "readPreference=primary,readPreference=secondary"} {
+		_, err = easymongo.ParseConnectionFlags(invalid)
+		is.Error(err, "'%s' should not parse", invalid)
+	}
+
+	is.NoError(easymongo.DefaultAnywhere.Validate())
+	is.Error((easymongo.ReadPreferencePrimary | easymongo.ReadPreferenceSecondary).Validate())
+	is.Error((easymongo.WriteConcernW1 | easymongo.WriteConcernW3).Validate())
+	is.Error(easymongo.ReadPreferenceWriteConcern.Validate())
+
+	_, err = easymongo.ConnectWith("mongodb://127.0.0.1:1").SkipGlobal().
+		Flags(easymongo.ReadConcernLocal | easymongo.ReadConcernMajority).Connect()
+	is.Error(err, "Connect should refuse conflicting flags")
+
+	// Conflicting flags fail the operation rather than being resolved silently
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").SkipGlobal().Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+	conflicting := easymongo.ReadPreferencePrimary | easymongo.ReadPreferenceSecondary
+	db := tmpConn.Database("batman_archive")
+	var e enemy
+	for name, err := range map[string]error{
+		"Database.WithFlags":   db.WithFlags(conflicting).C("enemies").Find(bson.M{}).One(&e),
+		"Collection.WithFlags": db.C("enemies").WithFlags(conflicting).Find(bson.M{}).One(&e),
+		"FindQuery.Flags":      db.C("enemies").Find(bson.M{}).Flags(conflicting).One(&e),
+		"Database.Run":         db.WithFlags(conflicting).Run(bson.M{"ping": 1}, &bson.M{}),
+	} {
+		if is.Error(err, name) {
+			is.Contains(err.Error(), "conflict", name)
+		}
+	}
+}
@@ -1,26 +1,107 @@
 package easymongo
 
-import "sync"
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
 
-// globalConnection is used to cache the most recent cluster connected to
-var globalConnection *Connection
+// DefaultConnectionName is the name a connection is registered under when ConnectionBuilder.Name() is not used.
+const DefaultConnectionName = "default"
 
-// connectionLock should be used whenever modifications are made to globalConnection
+// connections caches every connection that has been registered, keyed by name
+var connections = map[string]*Connection{}
+
+// defaultConnectionName is the name of the connection returned by GetCurrentConnection
+var defaultConnectionName string
+
+// connectionLock should be used whenever modifications are made to connections or defaultConnectionName
 var connectionLock sync.RWMutex
 
-// setGlobalConnection sets the cached global connection to the provided connection value.
+// setGlobalConnection registers the provided connection under its name.
+// A connection registered under DefaultConnectionName always becomes the default (which preserves the behavior
+// of caching the most recent cluster connected to). A named connection only becomes the default if AsDefault()
+// was specified or if there is no default yet. Connections built with SkipGlobal() are never registered.
 func setGlobalConnection(conn *Connection) {
+	if conn.mongoOptions.skipGlobal {
+		return
+	}
+	name := conn.Name()
+	connectionLock.Lock()
+	defer connectionLock.Unlock()
+	connections[name] = conn
+	if _, found := connections[defaultConnectionName]; !found ||
+		name == DefaultConnectionName || conn.mongoOptions.isDefault {
+		defaultConnectionName = name
+	}
+}
+
+// unsetGlobalConnection removes the provided connection from the registry (should it be registered).
+func unsetGlobalConnection(conn *Connection) {
+	name := conn.Name()
 	connectionLock.Lock()
 	defer connectionLock.Unlock()
-	globalConnection = conn
+	if connections[name] != conn {
+		// A different connection has since been registered under this name
+		return
+	}
+	delete(connections, name)
+	if defaultConnectionName == name {
+		defaultConnectionName = ""
+	}
 }
 
-// GetCurrentConnection returns the current connection cached in the global context.
+// GetCurrentConnection returns the default connection cached in the global context.
 func GetCurrentConnection() *Connection {
 	connectionLock.RLock()
 	defer connectionLock.RUnlock()
-	if globalConnection == nil {
+	conn, found := connections[defaultConnectionName]
+	if !found {
 		panic("Connect() or ConnectWith() must be called prior to GetCurrentConnection()")
 	}
-	return globalConnection
+	return conn
+}
+
+// GetConnection returns the connection registered under the provided name.
+// e.g. GetConnection("analytics") after calling ConnectWith(mongoURI).Name("analytics").Connect()
+// If no connection has been registered under the name, a panic occurs. Use LookupConnection to check first.
+func GetConnection(name string) *Connection {
+	conn, found := LookupConnection(name)
+	if !found {
+		panic(fmt.Sprintf("no connection named '%s' has been registered - use ConnectWith().Name(%q).Connect() first", name, name))
+	}
+	return conn
+}
+
+// LookupConnection returns the connection registered under the provided name.
+// found is false if no such connection has been registered.
+func LookupConnection(name string) (conn *Connection, found bool) {
+	connectionLock.RLock()
+	defer connectionLock.RUnlock()
+	conn, found = connections[name]
+	return conn, found
+}
+
+// SetDefaultConnection designates the named connection as the one returned by GetCurrentConnection (and consumed
+// by GetDatabase/GetCollection). An error is returned if no connection has been registered under the name.
+func SetDefaultConnection(name string) error {
+	connectionLock.Lock()
+	defer connectionLock.Unlock()
+	if _, found := connections[name]; !found {
+		return fmt.Errorf("no connection named '%s' has been registered", name)
+	}
+	defaultConnectionName = name
+	return nil
+}
+
+// ConnectionNames returns the sorted names of all registered connections.
+func ConnectionNames() []string {
+	connectionLock.RLock()
+	defer connectionLock.RUnlock()
+	names := make([]string, 0, len(connections))
+	for name := range connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
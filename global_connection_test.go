@@ -0,0 +1,44 @@
+package easymongo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+)
+
+func TestConnectionRegistry(t *testing.T) {
+	operationalURI := "mongodb://127.0.0.1:27017"
+	analyticsURI := "mongodb://127.0.0.1:27018"
+	t.Run("Named connections", func(t *testing.T) {
+		is := assert.New(t)
+		operational, err := easymongo.ConnectWith(operationalURI).Connect()
+		is.NoError(err)
+		analytics, err := easymongo.ConnectWith(analyticsURI).Name("analytics").Connect()
+		is.NoError(err)
+		is.Equal("analytics", analytics.Name())
+		is.Equal(easymongo.DefaultConnectionName, operational.Name())
+
+		is.Equal(analytics, easymongo.GetConnection("analytics"), "The named connection should be registered")
+		is.Equal(operational, easymongo.GetCurrentConnection(), "A named connection should not replace the default")
+		is.Equal(analyticsURI, easymongo.GetDatabaseOn("analytics", "reports").Collection("daily").Connection().MongoURI())
+		is.Contains(easymongo.ConnectionNames(), "analytics")
+	})
+	t.Run("AsDefault", func(t *testing.T) {
+		is := assert.New(t)
+		analytics, err := easymongo.ConnectWith(analyticsURI).Name("analytics").AsDefault().Connect()
+		is.NoError(err)
+		is.Equal(analytics, easymongo.GetCurrentConnection())
+		is.NoError(easymongo.SetDefaultConnection(easymongo.DefaultConnectionName))
+		is.NotEqual(analytics, easymongo.GetCurrentConnection())
+		is.Error(easymongo.SetDefaultConnection("does-not-exist"))
+	})
+	t.Run("SkipGlobal", func(t *testing.T) {
+		is := assert.New(t)
+		_, err := easymongo.ConnectWith(analyticsURI).Name("private").SkipGlobal().Connect()
+		is.NoError(err)
+		_, found := easymongo.LookupConnection("private")
+		is.False(found, "A connection using SkipGlobal should not be registered")
+		is.Panics(func() { easymongo.GetConnection("private") })
+	})
+}
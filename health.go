@@ -0,0 +1,267 @@
+package easymongo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// HealthStatus summarizes the result of a health check
+type HealthStatus string
+
+const (
+	// HealthUnknown means no health check has completed yet
+	HealthUnknown HealthStatus = "unknown"
+	// HealthHealthy means the cluster responded to a ping and a primary is available
+	HealthHealthy HealthStatus = "healthy"
+	// HealthDegraded means the cluster responded to a ping, but no primary is available (so writes will fail)
+	HealthDegraded HealthStatus = "degraded"
+	// HealthUnhealthy means the cluster could not be pinged
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// commandNotFoundCode is returned by servers which predate the hello command
+const commandNotFoundCode = 59
+
+// Health represents the result of the most recent health check against a connection.
+type Health struct {
+	// Status summarizes the health of the connection
+	Status HealthStatus
+	// LastError is the error returned by the most recent check (if any)
+	LastError error
+	// Latency is the round trip time of the most recent ping
+	Latency time.Duration
+	// Primary is the address of the primary node (should the cluster be a replica set)
+	Primary string
+	// CheckedAt is when the most recent check completed
+	CheckedAt time.Time
+	// Circuit is the current state of the circuit breaker (empty if CircuitBreaker() was not used)
+	Circuit CircuitState
+}
+
+// HealthChangeFunc is called whenever the Status of a connection changes.
+type HealthChangeFunc func(previous, current Health)
+
+// healthMonitor holds the health state of a connection
+type healthMonitor struct {
+	lock      sync.RWMutex
+	current   Health
+	callbacks []HealthChangeFunc
+	// stop ends the background health checks
+	stop context.CancelFunc
+}
+
+// HealthCheck runs a ping and a hello topology check against the cluster in the background at the provided interval.
+// The result of the most recent check is available using Connection.Health().
+// e.g. ConnectWith(mongoURI).HealthCheck(10 * time.Second).OnHealthChange(alertFunc).Connect()
+func (cb *ConnectionBuilder) HealthCheck(interval time.Duration) *ConnectionBuilder {
+	cb.connection.mongoOptions.runHealthCheckOnConnection = true
+	cb.connection.mongoOptions.healthCheckInterval = interval
+	return cb
+}
+
+// OnHealthChange registers a callback which is called whenever the health status of the connection changes.
+// Callbacks are called from the health check goroutine, so they should not block.
+func (cb *ConnectionBuilder) OnHealthChange(fn HealthChangeFunc) *ConnectionBuilder {
+	cb.connection.health.callbacks = append(cb.connection.health.callbacks, fn)
+	return cb
+}
+
+// Health returns the result of the most recent health check. If background health checks
+// were not enabled using HealthCheck(), the status is HealthUnknown until CheckHealth() is called.
+func (conn *Connection) Health() Health {
+	conn.health.lock.RLock()
+	defer conn.health.lock.RUnlock()
+	health := conn.health.current
+	if health.Status == "" {
+		health.Status = HealthUnknown
+	}
+	health.Circuit = conn.breaker.circuitState()
+	return health
+}
+
+// CheckHealth runs a health check immediately, records the result and returns it.
+func (conn *Connection) CheckHealth() Health {
+	health := conn.checkHealth()
+	conn.health.lock.Lock()
+	previous := conn.health.current
+	conn.health.current = health
+	callbacks := conn.health.callbacks
+	conn.health.lock.Unlock()
+	health.Circuit = conn.breaker.circuitState()
+
+	if previous.Status != health.Status {
+		if previous.Status == "" {
+			previous.Status = HealthUnknown
+		}
+		log := conn.logger().WithFields(Fields{"previous": previous.Status, "status": health.Status, "latency": health.Latency})
+		if health.Status == HealthHealthy {
+			log.Infof("Mongo health changed from %s to %s", previous.Status, health.Status)
+		} else {
+			log.WithField("error", health.LastError).Warnf("Mongo health changed from %s to %s", previous.Status, health.Status)
+		}
+		for _, fn := range callbacks {
+			fn(previous, health)
+		}
+	}
+	return health
+}
+
+// helloResult holds the fields we care about from the hello (or legacy isMaster) command
+type helloResult struct {
+	IsWritablePrimary bool   `bson:"isWritablePrimary"`
+	IsMaster          bool   `bson:"ismaster"`
+	Primary           string `bson:"primary"`
+	SetName           string `bson:"setName"`
+	Me                string `bson:"me"`
+}
+
+// checkHealth pings the cluster and inspects its topology
+func (conn *Connection) checkHealth() (health Health) {
+	defer func() { health.CheckedAt = time.Now() }()
+	start := time.Now()
+	if err := conn.Ping(); err != nil {
+		health.Status = HealthUnhealthy
+		health.LastError = err
+		return health
+	}
+	health.Latency = time.Since(start)
+
+	hello, err := conn.hello()
+	if err != nil {
+		health.Status = HealthDegraded
+		health.LastError = err
+		return health
+	}
+	health.Primary = hello.Primary
+	if health.Primary == "" && hello.SetName == "" && (hello.IsWritablePrimary || hello.IsMaster) {
+		// A standalone instance is its own primary
+		health.Primary = hello.Me
+	}
+	health.Status = HealthHealthy
+	if hello.SetName != "" && hello.Primary == "" {
+		health.Status = HealthDegraded
+		health.LastError = errors.New("no primary is available in the replica set")
+	}
+	return health
+}
+
+// hello runs the hello command against the nearest node, falling back to isMaster for older servers
+func (conn *Connection) hello() (result helloResult, err error) {
+	ctx, cancel := conn.operationCtx()
+	defer cancel()
+	opts := options.RunCmd().SetReadPreference(readpref.Nearest())
+	err = conn.track(func() error {
+		admin := conn.mongoClient().Database("admin")
+		err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}, opts).Decode(&result)
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == commandNotFoundCode {
+			err = admin.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}, opts).Decode(&result)
+		}
+		return err
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = ErrTimeoutOccurred
+	}
+	return result, err
+}
+
+// monitorHealth runs health checks at the provided interval until ctx is done
+func (conn *Connection) monitorHealth(ctx context.Context, interval time.Duration) {
+	conn.CheckHealth()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn.CheckHealth()
+		}
+	}
+}
+
+// startHealthMonitor starts background health checks (if they were requested using HealthCheck())
+func (conn *Connection) startHealthMonitor() {
+	if !conn.mongoOptions.runHealthCheckOnConnection || conn.mongoOptions.healthCheckInterval <= 0 {
+		return
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	conn.health.stop = stop
+	go conn.monitorHealth(ctx, conn.mongoOptions.healthCheckInterval)
+}
+
+// stopHealthMonitor stops background health checks (should they be running)
+func (conn *Connection) stopHealthMonitor() {
+	if conn.health.stop != nil {
+		conn.health.stop()
+	}
+}
+
+// healthResponse is the JSON body served by the health handlers
+type healthResponse struct {
+	Status    HealthStatus `json:"status"`
+	Error     string       `json:"error,omitempty"`
+	LatencyMS float64      `json:"latencyMs"`
+	Primary   string       `json:"primary,omitempty"`
+	CheckedAt *time.Time   `json:"checkedAt,omitempty"`
+	Circuit   CircuitState `json:"circuit,omitempty"`
+}
+
+// writeHealth writes the health as JSON using the provided status code
+func writeHealth(w http.ResponseWriter, code int, health Health) {
+	resp := healthResponse{
+		Status:    health.Status,
+		LatencyMS: float64(health.Latency) / float64(time.Millisecond),
+		Primary:   health.Primary,
+		Circuit:   health.Circuit,
+	}
+	if health.LastError != nil {
+		resp.Error = health.LastError.Error()
+	}
+	if !health.CheckedAt.IsZero() {
+		resp.CheckedAt = &health.CheckedAt
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// LivenessHandler returns an http.Handler suitable for a liveness probe. It responds with
+// 200 OK until the connection is closed, as a degraded cluster is not a reason to restart a process.
+func (conn *Connection) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		code := http.StatusOK
+		if conn.IsClosed() {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealth(w, code, conn.Health())
+	})
+}
+
+// ReadinessHandler returns an http.Handler suitable for a readiness probe. It responds with 200 OK
+// when the most recent health check was healthy or degraded and 503 Service Unavailable otherwise
+// (including while the circuit breaker is open).
+// If background health checks were not enabled using HealthCheck(), a check is run on every request.
+func (conn *Connection) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		health := conn.Health()
+		if !conn.mongoOptions.runHealthCheckOnConnection {
+			health = conn.CheckHealth()
+		}
+		code := http.StatusOK
+		if conn.IsClosed() || health.Circuit == CircuitOpen ||
+			(health.Status != HealthHealthy && health.Status != HealthDegraded) {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealth(w, code, health)
+	})
+}
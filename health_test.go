@@ -0,0 +1,55 @@
+package easymongo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+)
+
+func TestHealth(t *testing.T) {
+	is := assert.New(t)
+	changes := make(chan easymongo.Health, 1)
+	// Nothing is listening on this port, so every check should fail
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		DefaultOperationTimeout(100 * time.Millisecond).
+		HealthCheck(50 * time.Millisecond).
+		OnHealthChange(func(previous, current easymongo.Health) {
+			select {
+			case changes <- current:
+			default:
+			}
+		}).Connect()
+	is.NoError(err)
+
+	select {
+	case health := <-changes:
+		is.Equal(easymongo.HealthUnhealthy, health.Status)
+		is.Error(health.LastError)
+		is.False(health.CheckedAt.IsZero())
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnHealthChange was never called")
+	}
+	is.Equal(easymongo.HealthUnhealthy, tmpConn.Health().Status)
+
+	rec := httptest.NewRecorder()
+	tmpConn.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	is.Equal(http.StatusServiceUnavailable, rec.Code, "An unreachable cluster should not be ready")
+	is.Contains(rec.Body.String(), `"status":"unhealthy"`)
+
+	rec = httptest.NewRecorder()
+	tmpConn.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	is.Equal(http.StatusOK, rec.Code, "An unreachable cluster should not fail liveness")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	is.NoError(tmpConn.Close(ctx))
+	rec = httptest.NewRecorder()
+	tmpConn.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	is.Equal(http.StatusServiceUnavailable, rec.Code, "A closed connection should fail liveness")
+}
@@ -1,6 +1,8 @@
 package easymongo
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/x/bsonx"
@@ -27,9 +29,12 @@ func (i *Index) Ensure() (indexName string, err error) {
 	opts := options.CreateIndexes()
 	// TODO: Index.Ensure() options
 	// TODO: Support compound index
-	indexName, err = i.collection.mongoColl.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bsonx.Doc{{Key: i.indexNames[0], Value: bsonx.Int32(1)}},
-	}, opts)
+	err = i.collection.run(ctx, opEnsureIndex, nil, func(ctx context.Context) (err error) {
+		indexName, err = i.collection.mongoCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bsonx.Doc{{Key: i.indexNames[0], Value: bsonx.Int32(1)}},
+		}, opts)
+		return err
+	})
 	err = i.collection.handleErr(err)
 	return indexName, err
 }
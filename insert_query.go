@@ -1,15 +1,21 @@
 package easymongo
 
 import (
+	"context"
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // InsertQuery is a helper for constructing insertion operations
 type InsertQuery struct {
 	*Query
+	unordered bool
 }
 
 // Insert constructs and returns an InsertQuery object.
@@ -20,13 +26,62 @@ func (c *Collection) Insert() *InsertQuery {
 	}
 }
 
+// WithContext will consume the supplied context. A note that this is completely optional
+// as Timeout will auto-create a context for you if one is not supplied.
+// This is typically useful when using a context across many functions (e.g. to propagate a trace).
+func (iq *InsertQuery) WithContext(ctx context.Context) *InsertQuery {
+	iq.Query.setContext(&ctx)
+	return iq
+}
+
+// Retry overrides the connection's RetryPolicy for this query. Use RetryPolicy{MaxAttempts: 1} to disable retrying.
+func (iq *InsertQuery) Retry(policy RetryPolicy) *InsertQuery {
+	iq.Query.setRetry(policy)
+	return iq
+}
+
+// ReadPreference overrides the read preference for this query only.
+// e.g. ReadPreference(readpref.SecondaryPreferred())
+func (iq *InsertQuery) ReadPreference(rp *readpref.ReadPref) *InsertQuery {
+	iq.Query.setReadPreference(rp)
+	return iq
+}
+
+// ReadConcern overrides the read concern for this query only.
+func (iq *InsertQuery) ReadConcern(rc *readconcern.ReadConcern) *InsertQuery {
+	iq.Query.setReadConcern(rc)
+	return iq
+}
+
+// WriteConcern overrides the write concern for this query only.
+func (iq *InsertQuery) WriteConcern(wc *writeconcern.WriteConcern) *InsertQuery {
+	iq.Query.setWriteConcern(wc)
+	return iq
+}
+
+// Flags overrides the read concern, read preference and write concern set in flags for this query only.
+// e.g. Flags(ReadPreferenceSecondaryPreferred | ReadConcernMajority)
+func (iq *InsertQuery) Flags(flags ConnectionFlag) *InsertQuery {
+	iq.Query.setFlags(flags)
+	return iq
+}
+
+// Unordered continues inserting the remaining documents passed to Many should one of them fail.
+// By default, Many stops at the first failure, so a DuplicateKeyErrors only ever holds a single position.
+func (iq *InsertQuery) Unordered() *InsertQuery {
+	iq.unordered = true
+	return iq
+}
+
 // One is used to insert a single object into a collection
 func (iq *InsertQuery) One(objToInsert interface{}) (id *primitive.ObjectID, err error) {
-	ctx, cancelFunc := iq.getContext()
-	defer cancelFunc()
+	var result *mongo.InsertOneResult
 	// TODO: InsertOne options
 	opts := options.InsertOne()
-	result, err := iq.collection.mongoColl.InsertOne(ctx, objToInsert, opts)
+	err = iq.run(opInsertOne, func(ctx context.Context) (err error) {
+		result, err = iq.collection.mongoCollection().InsertOne(ctx, objToInsert, opts)
+		return err
+	})
 	if result != nil && !interfaceIsZero(result.InsertedID) {
 		if rid, ok := result.InsertedID.(primitive.ObjectID); ok {
 			id = &rid
@@ -57,12 +112,17 @@ func (iq *InsertQuery) Many(objsToInsert interface{}) (ids []*primitive.ObjectID
 // If you need to insert large quantities of items and every nanosecond matters,
 // then use this function instead of Many.
 func (iq *InsertQuery) ManyFromInterfaceSlice(objsToInsert []interface{}) (ids []*primitive.ObjectID, err error) {
-	ctx, cancelFunc := iq.getContext()
-	defer cancelFunc()
+	var result *mongo.InsertManyResult
 	// TODO: InsertMany options
 	opts := options.InsertMany()
+	if iq.unordered {
+		opts.SetOrdered(false)
+	}
 
-	result, err := iq.collection.mongoColl.InsertMany(ctx, objsToInsert, opts)
+	err = iq.run(opInsertMany, func(ctx context.Context) (err error) {
+		result, err = iq.collection.mongoCollection().InsertMany(ctx, objsToInsert, opts)
+		return err
+	})
 	if err != nil {
 		return ids, err
 	}
@@ -1,10 +1,15 @@
 package easymongo_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestInsert(t *testing.T) {
@@ -28,7 +33,6 @@ func TestInsert(t *testing.T) {
 		is.NoError(err, "Could not look-up the zeus record in the DB")
 		is.Equal("Zeus", zeusLookup.Name, "The record appears to have improper information in it")
 	})
-	// TODO: Test IsDup failure path
 	t.Run("Insert().Many()", func(t *testing.T) {
 		is := assert.New(t)
 		ids, err := coll.Insert().Many(&[]greekGod{{Name: "Hera"}, {Name: "Hades"}})
@@ -39,4 +43,56 @@ func TestInsert(t *testing.T) {
 		is.NoError(err, "Could not find any documents after insertion")
 		is.Len(godLookup, 3, "After the previous test and this test, there should be 3 documents")
 	})
+	t.Run("Duplicate keys", func(t *testing.T) {
+		is := assert.New(t)
+		titans := conn.Database(dbName).C("titans")
+		_, err := titans.MongoDriverCollection().Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		is.NoError(err)
+		_, err = titans.Insert().One(greekGod{Name: "Cronus"})
+		is.NoError(err)
+
+		_, err = titans.Insert().One(greekGod{Name: "Cronus"})
+		var dupErr easymongo.DuplicateKeyError
+		is.True(errors.As(err, &dupErr), "A DuplicateKeyError should be returned")
+		is.True(easymongo.IsDuplicateKey(err))
+		is.Equal("name_1", dupErr.Index)
+		is.Equal(map[string]interface{}{"name": "Cronus"}, dupErr.Keys)
+
+		_, err = titans.Insert().Unordered().Many([]greekGod{{Name: "Cronus"}, {Name: "Rhea"}, {Name: "Rhea"}})
+		var dupErrs easymongo.DuplicateKeyErrors
+		is.True(errors.As(err, &dupErrs), "DuplicateKeyErrors should be returned by Many")
+		if is.Len(dupErrs.Errors, 2, "Every failed position should be reported") {
+			is.Equal(0, dupErrs.Errors[0].WriteIndex)
+			is.Equal(2, dupErrs.Errors[1].WriteIndex)
+			is.Equal(map[string]interface{}{"name": "Rhea"}, dupErrs.Errors[1].Keys)
+		}
+	})
+	t.Run("Nil slices and maps", func(t *testing.T) {
+		is := assert.New(t)
+		type shrine struct {
+			Name       string            `bson:"name"`
+			Offerings  []string          `bson:"offerings"`
+			Attributes map[string]string `bson:"attributes"`
+		}
+		var stored bson.M
+		id, err := coll.Insert().One(shrine{Name: "Delphi"})
+		is.NoError(err)
+		is.NoError(coll.FindByID(id, &stored))
+		is.Equal(bson.A{}, stored["offerings"], "Nil slices should be stored as empty arrays by default")
+		is.Equal(bson.M{}, stored["attributes"], "Nil maps should be stored as empty documents by default")
+
+		tmpConn, err := easymongo.ConnectWith(conn.MongoURI()).SkipGlobal().NilSlicesAreNull(true).NilMapsAreNull(true).Connect()
+		is.NoError(err)
+		defer tmpConn.Close(context.Background())
+		tmpColl := tmpConn.Database(dbName).C(collName)
+		id, err = tmpColl.Insert().One(shrine{Name: "Olympia"})
+		is.NoError(err)
+		stored = bson.M{}
+		is.NoError(tmpColl.FindByID(id, &stored))
+		is.Nil(stored["offerings"], "Nil slices should be stored as null when requested")
+		is.Nil(stored["attributes"], "Nil maps should be stored as null when requested")
+	})
 }
@@ -0,0 +1,121 @@
+package easymongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// lifecycle tracks whether a connection has been closed and how many operations are still running against it.
+type lifecycle struct {
+	lock     sync.Mutex
+	closed   bool
+	inFlight int
+	// drained is closed once the connection is closed and no operations remain in flight
+	drained chan struct{}
+}
+
+// beginOperation registers an operation as in flight. ErrConnectionClosed is returned if
+// the connection has been closed.
+func (conn *Connection) beginOperation() error {
+	conn.lifecycle.lock.Lock()
+	defer conn.lifecycle.lock.Unlock()
+	if conn.lifecycle.closed {
+		return ErrConnectionClosed
+	}
+	conn.lifecycle.inFlight++
+	return nil
+}
+
+// endOperation marks an operation started with beginOperation as complete.
+func (conn *Connection) endOperation() {
+	conn.lifecycle.lock.Lock()
+	defer conn.lifecycle.lock.Unlock()
+	conn.lifecycle.inFlight--
+	if conn.lifecycle.closed && conn.lifecycle.inFlight == 0 {
+		close(conn.lifecycle.drained)
+	}
+}
+
+// track runs fn as an in-flight operation. fn is not run (and ErrConnectionClosed is returned)
+// if the connection has been closed.
+func (conn *Connection) track(fn func() error) error {
+	if err := conn.beginOperation(); err != nil {
+		return err
+	}
+	defer conn.endOperation()
+	return fn()
+}
+
+// trackIn runs fn as an in-flight operation of tx. While tx is running, the transaction is already counted
+// as in flight, so fn is run even if the connection is closing - letting Close wait for the transaction to commit.
+// Without a running transaction, trackIn behaves as track.
+func (conn *Connection) trackIn(tx *Tx, fn func() error) error {
+	if tx.running(conn) {
+		return fn()
+	}
+	return conn.track(fn)
+}
+
+// IsClosed returns true once Close has been called on the connection.
+func (conn *Connection) IsClosed() bool {
+	conn.lifecycle.lock.Lock()
+	defer conn.lifecycle.lock.Unlock()
+	return conn.lifecycle.closed
+}
+
+// Close gracefully shuts down the connection. New operations are refused with ErrConnectionClosed, while
+// operations that are already running (including transactions, which may still run further queries and commit)
+// are given until ctx is done to complete. The underlying mongo.Client
+// is then disconnected and the connection is removed from the global connection registry.
+// If operations were still running when ctx was done, the client is disconnected anyway and ErrTimeoutOccurred
+// is returned. Calling Close more than once returns ErrConnectionClosed.
+//
+// Database and Collection objects derived from the connection return ErrConnectionClosed once it is closed.
+func (conn *Connection) Close(ctx context.Context) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	conn.lifecycle.lock.Lock()
+	if conn.lifecycle.closed {
+		conn.lifecycle.lock.Unlock()
+		return ErrConnectionClosed
+	}
+	conn.lifecycle.closed = true
+	conn.lifecycle.drained = make(chan struct{})
+	if conn.lifecycle.inFlight == 0 {
+		close(conn.lifecycle.drained)
+	}
+	conn.lifecycle.lock.Unlock()
+
+	unsetGlobalConnection(conn)
+	if conn.stopCredentialWatch != nil {
+		conn.stopCredentialWatch()
+	}
+	conn.stopHealthMonitor()
+
+	select {
+	case <-conn.lifecycle.drained:
+	case <-ctx.Done():
+		err = ErrTimeoutOccurred
+	}
+
+	client := conn.mongoClient()
+	if client == nil {
+		return err
+	}
+	disconnectCtx := ctx
+	if ctx.Err() != nil {
+		// Still give the driver a chance to clean up its connection pool
+		var cancel context.CancelFunc
+		disconnectCtx, cancel = conn.operationCtx()
+		defer cancel()
+	}
+	if disconnectErr := client.Disconnect(disconnectCtx); disconnectErr != nil && err == nil &&
+		!errors.Is(disconnectErr, mongo.ErrClientDisconnected) {
+		err = disconnectErr
+	}
+	return err
+}
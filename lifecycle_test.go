@@ -0,0 +1,34 @@
+package easymongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClose(t *testing.T) {
+	is := assert.New(t)
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:27017").Name("closing").Connect()
+	is.NoError(err)
+	coll := tmpConn.Database("batman_archive").C("enemies")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	is.NoError(tmpConn.Close(ctx), "Closing an idle connection should succeed")
+	is.True(tmpConn.IsClosed())
+	_, found := easymongo.LookupConnection("closing")
+	is.False(found, "A closed connection should be removed from the registry")
+
+	var e enemy
+	err = coll.Find(bson.M{}).One(&e)
+	is.True(errors.Is(err, easymongo.ErrConnectionClosed), "Queries on a closed connection should fail with ErrConnectionClosed")
+	_, err = coll.Insert().One(e)
+	is.True(errors.Is(err, easymongo.ErrConnectionClosed), "Inserts on a closed connection should fail with ErrConnectionClosed")
+	is.True(errors.Is(tmpConn.Ping(), easymongo.ErrConnectionClosed))
+	is.True(errors.Is(tmpConn.Close(ctx), easymongo.ErrConnectionClosed), "Closing twice should fail")
+}
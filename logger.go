@@ -1,24 +1,53 @@
 package easymongo
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 )
 
-// Logger represents a common interface that is consumed by easymongo. Add wrappers around
-//     e.g. ConnectWith().Logger(Logger).Connect()
+// Fields holds structured key/value pairs which are attached to a log line
+type Fields map[string]interface{}
+
+// Logger represents a common interface that is consumed by easymongo. It is a leveled, structured logger.
+// Adapters are provided for logrus (NewLogrusLogger) and log/slog (NewSlogLogger).
+// e.g. ConnectWith().Logger(Logger).Connect()
 type Logger interface {
-	// WithField(msg string, arg interface{}) Logger
-	// WithFields(fields map[string]interface{}) Logger
+	// WithField returns a Logger which attaches the key/value pair to every line it logs
+	WithField(key string, value interface{}) Logger
+	// WithFields returns a Logger which attaches all of fields to every line it logs
+	WithFields(fields Fields) Logger
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// SimpleLogger is the two method interface easymongo originally consumed. A SimpleLogger passed to
+// ConnectionBuilder.Logger() or Connection.SetLogger() which does not implement Logger is wrapped, with
+// Info lines logged using Debugf, Warn lines logged using Errorf and the fields appended to the message.
+type SimpleLogger interface {
 	Debugf(format string, args ...interface{})
-	// Debugf(format string, args ...interface{})
-	// Info(args ...interface{})
-	// Infof(format string, args ...interface{})
-	// Warn(args ...interface{})
-	// Warnf(format string, args ...interface{})
 	Errorf(format string, args ...interface{})
-	// Errorf(format string, args ...interface{})
 }
 
+// toLogger returns logger as a Logger, wrapping it if needed
+func toLogger(logger SimpleLogger) Logger {
+	switch l := logger.(type) {
+	case nil:
+		return nil
+	case Logger:
+		return l
+	case logrus.FieldLogger:
+		// *logrus.Logger and *logrus.Entry return a *logrus.Entry from WithField, so they don't implement Logger
+		return NewLogrusLogger(l)
+	}
+	return &simpleLogger{logger: logger}
+}
+
+// DefaultLogger is the Logger used when one is not provided. It is also the logrus adapter.
 type DefaultLogger struct {
 	logger *logrus.Entry
 }
@@ -26,8 +55,8 @@ type DefaultLogger struct {
 // NewDefaultLogger returns a DefaultLogger, which implements the Logger interface
 // Under the covers, DefaultLogger calls out to logrus using TextFormatter set
 // to the debug level.
-// If you don't want this behavior, simply implement an interface similar to this one
-// that supports Debugf() and Errorf() calls.
+// If you don't want this behavior, pass any Logger (or a SimpleLogger, which
+// supports Debugf() and Errorf() calls) to ConnectionBuilder.Logger().
 func NewDefaultLogger() *DefaultLogger {
 	l := logrus.New().WithField("src", "easymongo")
 	l.Logger.SetFormatter(&logrus.TextFormatter{
@@ -40,10 +69,108 @@ func NewDefaultLogger() *DefaultLogger {
 	}
 }
 
+// NewLogrusLogger adapts an existing logrus logger (a *logrus.Logger or *logrus.Entry) to the Logger interface.
+// The level and formatting of the logrus logger are left untouched.
+func NewLogrusLogger(logger logrus.FieldLogger) *DefaultLogger {
+	return &DefaultLogger{
+		logger: logger.WithFields(logrus.Fields{}),
+	}
+}
+
+func (logger *DefaultLogger) WithField(key string, value interface{}) Logger {
+	return &DefaultLogger{logger: logger.logger.WithField(key, value)}
+}
+
+func (logger *DefaultLogger) WithFields(fields Fields) Logger {
+	return &DefaultLogger{logger: logger.logger.WithFields(logrus.Fields(fields))}
+}
+
 func (logger *DefaultLogger) Debugf(format string, args ...interface{}) {
 	logger.logger.Debugf(format, args...)
 }
 
+func (logger *DefaultLogger) Infof(format string, args ...interface{}) {
+	logger.logger.Infof(format, args...)
+}
+
+func (logger *DefaultLogger) Warnf(format string, args ...interface{}) {
+	logger.logger.Warnf(format, args...)
+}
+
 func (logger *DefaultLogger) Errorf(format string, args ...interface{}) {
 	logger.logger.Errorf(format, args...)
 }
+
+// simpleLogger adapts a SimpleLogger to the Logger interface
+type simpleLogger struct {
+	logger SimpleLogger
+	fields Fields
+}
+
+func (sl *simpleLogger) WithField(key string, value interface{}) Logger {
+	return sl.WithFields(Fields{key: value})
+}
+
+func (sl *simpleLogger) WithFields(fields Fields) Logger {
+	merged := make(Fields, len(sl.fields)+len(fields))
+	for k, v := range sl.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &simpleLogger{logger: sl.logger, fields: merged}
+}
+
+func (sl *simpleLogger) Debugf(format string, args ...interface{}) {
+	sl.logger.Debugf("%s", sl.format(format, args))
+}
+
+func (sl *simpleLogger) Infof(format string, args ...interface{}) {
+	sl.logger.Debugf("%s", sl.format(format, args))
+}
+
+func (sl *simpleLogger) Warnf(format string, args ...interface{}) {
+	sl.logger.Errorf("%s", sl.format(format, args))
+}
+
+func (sl *simpleLogger) Errorf(format string, args ...interface{}) {
+	sl.logger.Errorf("%s", sl.format(format, args))
+}
+
+// format renders the message with the fields appended as sorted key=value pairs
+func (sl *simpleLogger) format(format string, args []interface{}) string {
+	msg := fmt.Sprintf(format, args...)
+	if len(sl.fields) == 0 {
+		return msg
+	}
+	keys := make([]string, 0, len(sl.fields))
+	for k := range sl.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(msg)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, " %s=%v", k, sl.fields[k])
+	}
+	return sb.String()
+}
+
+// nopLogger discards everything. It is used when no logger has been set.
+type nopLogger struct{}
+
+func (nopLogger) WithField(string, interface{}) Logger { return nopLogger{} }
+func (nopLogger) WithFields(Fields) Logger             { return nopLogger{} }
+func (nopLogger) Debugf(string, ...interface{})        {}
+func (nopLogger) Infof(string, ...interface{})         {}
+func (nopLogger) Warnf(string, ...interface{})         {}
+func (nopLogger) Errorf(string, ...interface{})        {}
+
+// logger returns the connection's Logger (or a no-op Logger if none was set) with the connection name attached
+func (conn *Connection) logger() Logger {
+	if conn.log == nil {
+		return nopLogger{}
+	}
+	return conn.log.WithField("connection", conn.Name())
+}
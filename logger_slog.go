@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+package easymongo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// SlogLogger adapts a *slog.Logger to the Logger interface.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger which writes to the provided *slog.Logger (or slog.Default() if it is nil).
+// e.g. ConnectWith(mongoURI).Logger(NewSlogLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil)))).Connect()
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{logger: logger}
+}
+
+func (sl *SlogLogger) WithField(key string, value interface{}) Logger {
+	return &SlogLogger{logger: sl.logger.With(key, value)}
+}
+
+func (sl *SlogLogger) WithFields(fields Fields) Logger {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		args = append(args, k, fields[k])
+	}
+	return &SlogLogger{logger: sl.logger.With(args...)}
+}
+
+func (sl *SlogLogger) Debugf(format string, args ...interface{}) {
+	sl.log(slog.LevelDebug, format, args)
+}
+
+func (sl *SlogLogger) Infof(format string, args ...interface{}) {
+	sl.log(slog.LevelInfo, format, args)
+}
+
+func (sl *SlogLogger) Warnf(format string, args ...interface{}) {
+	sl.log(slog.LevelWarn, format, args)
+}
+
+func (sl *SlogLogger) Errorf(format string, args ...interface{}) {
+	sl.log(slog.LevelError, format, args)
+}
+
+// log only formats the message if the level is enabled
+func (sl *SlogLogger) log(level slog.Level, format string, args []interface{}) {
+	ctx := context.Background()
+	if !sl.logger.Enabled(ctx, level) {
+		return
+	}
+	sl.logger.Log(ctx, level, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,33 @@
+//go:build go1.21
+// +build go1.21
+
+package easymongo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+)
+
+func TestSlogLogger(t *testing.T) {
+	is := assert.New(t)
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	log := easymongo.NewSlogLogger(slog.New(handler))
+
+	log.WithFields(easymongo.Fields{"db": "batman_archive", "collection": "enemies"}).Infof("found %d enemies", 3)
+	var line map[string]interface{}
+	is.NoError(json.Unmarshal(buf.Bytes(), &line))
+	is.Equal("found 3 enemies", line["msg"])
+	is.Equal("INFO", line["level"])
+	is.Equal("batman_archive", line["db"])
+	is.Equal("enemies", line["collection"])
+
+	buf.Reset()
+	log.Debugf("below the handler level")
+	is.Empty(buf.String(), "Lines below the handler level should be dropped")
+}
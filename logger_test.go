@@ -0,0 +1,47 @@
+package easymongo_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// twoMethodLogger only implements the original Debugf/Errorf logger interface
+type twoMethodLogger struct {
+	lines []string
+}
+
+func (l *twoMethodLogger) Debugf(format string, args ...interface{}) {
+	l.lines = append(l.lines, "DEBUG "+fmt.Sprintf(format, args...))
+}
+
+func (l *twoMethodLogger) Errorf(format string, args ...interface{}) {
+	l.lines = append(l.lines, "ERROR "+fmt.Sprintf(format, args...))
+}
+
+func TestLogger(t *testing.T) {
+	is := assert.New(t)
+	log := &twoMethodLogger{}
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		DefaultQueryTimeout(100 * time.Millisecond).
+		Logger(log).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+
+	var e enemy
+	is.Error(tmpConn.Database("batman_archive").C("enemies").Find(bson.M{}).One(&e))
+	is.Len(log.lines, 1, "Each operation should be logged once")
+	line := strings.Join(log.lines, "\n")
+	is.True(strings.HasPrefix(line, "DEBUG FindQuery.One failed"), line)
+	for _, field := range []string{"db=batman_archive", "collection=enemies", "operation=FindQuery.One", "duration=", "connection=default"} {
+		is.Contains(line, field, "Fields should be appended to the message of a two method logger")
+	}
+}
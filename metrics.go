@@ -0,0 +1,248 @@
+package easymongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DefaultLatencyBuckets are the upper bounds (in seconds) of the latency histogram buckets.
+// They match the Prometheus client library defaults.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// LatencyHistogram counts operation durations into buckets.
+type LatencyHistogram struct {
+	// Buckets holds the upper bound (in seconds) of each bucket
+	Buckets []float64
+	// Counts holds the number of operations which took at most the matching bucket's upper bound.
+	// The counts are cumulative, as in Prometheus.
+	Counts []uint64
+	// Count is the total number of operations observed
+	Count uint64
+	// Sum is the total duration of all operations observed
+	Sum time.Duration
+}
+
+// OperationMetrics holds the metrics of one operation (e.g. FindQuery.All) against one collection.
+type OperationMetrics struct {
+	Database   string
+	Collection string
+	Operation  string
+	// Count is the number of times the operation was run
+	Count uint64
+	// Errors holds the number of failed operations, keyed by error code.
+	// The code is the server error code where one was returned, otherwise a short description (e.g. "timeout").
+	// mongo.ErrNoDocuments is not counted as an error.
+	Errors map[string]uint64
+	// Latency is the duration histogram of the operation
+	Latency LatencyHistogram
+}
+
+// metricsKey identifies an OperationMetrics
+type metricsKey struct {
+	db         string
+	collection string
+	operation  string
+}
+
+// operationMetrics records the metrics of a single operation
+type operationMetrics struct {
+	lock    sync.Mutex
+	metrics OperationMetrics
+}
+
+// metricsRegistry holds the metrics of every operation run against a connection
+type metricsRegistry struct {
+	lock       sync.RWMutex
+	operations map[metricsKey]*operationMetrics
+	// buckets overrides DefaultLatencyBuckets
+	buckets []float64
+}
+
+// LatencyBuckets overrides the upper bounds (in seconds) of the latency histogram buckets used by Metrics().
+// e.g. ConnectWith(mongoURI).LatencyBuckets(.001, .01, .1, 1).Connect()
+func (cb *ConnectionBuilder) LatencyBuckets(upperBounds ...float64) *ConnectionBuilder {
+	buckets := append([]float64(nil), upperBounds...)
+	sort.Float64s(buckets)
+	cb.connection.metrics.buckets = buckets
+	return cb
+}
+
+// record adds the result of an operation to the registry
+func (mr *metricsRegistry) record(key metricsKey, duration time.Duration, err error) {
+	mr.lock.RLock()
+	om, found := mr.operations[key]
+	mr.lock.RUnlock()
+	if !found {
+		mr.lock.Lock()
+		if om, found = mr.operations[key]; !found {
+			buckets := mr.buckets
+			if buckets == nil {
+				buckets = DefaultLatencyBuckets
+			}
+			om = &operationMetrics{metrics: OperationMetrics{
+				Database:   key.db,
+				Collection: key.collection,
+				Operation:  key.operation,
+				Errors:     map[string]uint64{},
+				Latency: LatencyHistogram{
+					Buckets: buckets,
+					Counts:  make([]uint64, len(buckets)),
+				},
+			}}
+			if mr.operations == nil {
+				mr.operations = map[metricsKey]*operationMetrics{}
+			}
+			mr.operations[key] = om
+		}
+		mr.lock.Unlock()
+	}
+
+	om.lock.Lock()
+	defer om.lock.Unlock()
+	m := &om.metrics
+	m.Count++
+	if code := metricsErrorCode(err); code != "" {
+		m.Errors[code]++
+	}
+	seconds := duration.Seconds()
+	for i, upperBound := range m.Latency.Buckets {
+		if seconds <= upperBound {
+			m.Latency.Counts[i]++
+		}
+	}
+	m.Latency.Count++
+	m.Latency.Sum += duration
+}
+
+// metricsErrorCode returns the label an error is counted under ("" if it should not be counted)
+func metricsErrorCode(err error) string {
+	switch {
+	case err == nil, errors.Is(err, mongo.ErrNoDocuments):
+		return ""
+	case errors.Is(err, ErrTimeoutOccurred), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrConnectionClosed):
+		return "connection_closed"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case serverErrorCode(err) != 0:
+		return strconv.Itoa(serverErrorCode(err))
+	case mongo.IsNetworkError(err):
+		return "network"
+	}
+	return "unknown"
+}
+
+// Metrics returns a snapshot of the metrics of every operation run against the connection,
+// sorted by database, collection and operation.
+func (conn *Connection) Metrics() []OperationMetrics {
+	conn.metrics.lock.RLock()
+	operations := make([]*operationMetrics, 0, len(conn.metrics.operations))
+	for _, om := range conn.metrics.operations {
+		operations = append(operations, om)
+	}
+	conn.metrics.lock.RUnlock()
+
+	snapshot := make([]OperationMetrics, 0, len(operations))
+	for _, om := range operations {
+		om.lock.Lock()
+		m := om.metrics
+		m.Errors = make(map[string]uint64, len(om.metrics.Errors))
+		for code, n := range om.metrics.Errors {
+			m.Errors[code] = n
+		}
+		m.Latency.Counts = append([]uint64(nil), om.metrics.Latency.Counts...)
+		om.lock.Unlock()
+		snapshot = append(snapshot, m)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		a, b := snapshot[i], snapshot[j]
+		if a.Database != b.Database {
+			return a.Database < b.Database
+		}
+		if a.Collection != b.Collection {
+			return a.Collection < b.Collection
+		}
+		return a.Operation < b.Operation
+	})
+	return snapshot
+}
+
+// MetricsHandler returns an http.Handler which renders Metrics() in the Prometheus text exposition format.
+// e.g. http.Handle("/metrics/mongo", conn.MetricsHandler())
+func (conn *Connection) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = conn.WriteMetrics(w)
+	})
+}
+
+// WriteMetrics writes Metrics() to w in the Prometheus text exposition format.
+func (conn *Connection) WriteMetrics(w io.Writer) error {
+	metrics := conn.Metrics()
+	var sb strings.Builder
+	connection := conn.Name()
+
+	sb.WriteString("# HELP easymongo_operations_total The number of operations run.\n")
+	sb.WriteString("# TYPE easymongo_operations_total counter\n")
+	for _, m := range metrics {
+		fmt.Fprintf(&sb, "easymongo_operations_total{%s} %d\n", metricLabels(connection, m), m.Count)
+	}
+
+	sb.WriteString("# HELP easymongo_operation_errors_total The number of operations which failed, by error code.\n")
+	sb.WriteString("# TYPE easymongo_operation_errors_total counter\n")
+	for _, m := range metrics {
+		codes := make([]string, 0, len(m.Errors))
+		for code := range m.Errors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(&sb, "easymongo_operation_errors_total{%s,code=\"%s\"} %d\n",
+				metricLabels(connection, m), escapeLabel(code), m.Errors[code])
+		}
+	}
+
+	sb.WriteString("# HELP easymongo_operation_duration_seconds The duration of operations.\n")
+	sb.WriteString("# TYPE easymongo_operation_duration_seconds histogram\n")
+	for _, m := range metrics {
+		labels := metricLabels(connection, m)
+		for i, upperBound := range m.Latency.Buckets {
+			fmt.Fprintf(&sb, "easymongo_operation_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(upperBound, 'g', -1, 64), m.Latency.Counts[i])
+		}
+		fmt.Fprintf(&sb, "easymongo_operation_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, m.Latency.Count)
+		fmt.Fprintf(&sb, "easymongo_operation_duration_seconds_sum{%s} %s\n",
+			labels, strconv.FormatFloat(m.Latency.Sum.Seconds(), 'g', -1, 64))
+		fmt.Fprintf(&sb, "easymongo_operation_duration_seconds_count{%s} %d\n", labels, m.Latency.Count)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// metricLabels renders the labels identifying an operation
+func metricLabels(connection string, m OperationMetrics) string {
+	return fmt.Sprintf(`connection="%s",db="%s",collection="%s",operation="%s"`,
+		escapeLabel(connection), escapeLabel(m.Database), escapeLabel(m.Collection), escapeLabel(m.Operation))
+}
+
+// labelEscaper escapes a Prometheus label value
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,57 @@
+package easymongo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMetrics(t *testing.T) {
+	is := assert.New(t)
+	// Nothing is listening on this port, so every operation times out
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		Name("metrics").
+		SkipGlobal().
+		DefaultQueryTimeout(50 * time.Millisecond).
+		LatencyBuckets(10, 0.001).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+
+	coll := tmpConn.Database("batman_archive").C("enemies")
+	var enemies []enemy
+	is.Error(coll.Find(bson.M{}).All(&enemies))
+	is.Error(coll.Find(bson.M{}).All(&enemies))
+
+	metrics := tmpConn.Metrics()
+	is.Len(metrics, 1)
+	if len(metrics) != 1 {
+		return
+	}
+	m := metrics[0]
+	is.Equal("batman_archive", m.Database)
+	is.Equal("enemies", m.Collection)
+	is.Equal("FindQuery.All", m.Operation)
+	is.Equal(uint64(2), m.Count)
+	is.Equal(map[string]uint64{"timeout": 2}, m.Errors)
+	is.Equal([]float64{0.001, 10}, m.Latency.Buckets, "Buckets should be sorted")
+	is.Equal([]uint64{0, 2}, m.Latency.Counts)
+	is.Equal(uint64(2), m.Latency.Count)
+
+	rec := httptest.NewRecorder()
+	tmpConn.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	labels := `connection="metrics",db="batman_archive",collection="enemies",operation="FindQuery.All"`
+	is.Contains(body, "# TYPE easymongo_operations_total counter\n")
+	is.Contains(body, "easymongo_operations_total{"+labels+"} 2\n")
+	is.Contains(body, "easymongo_operation_errors_total{"+labels+`,code="timeout"} 2`+"\n")
+	is.Contains(body, "easymongo_operation_duration_seconds_bucket{"+labels+`,le="0.001"} 0`+"\n")
+	is.Contains(body, "easymongo_operation_duration_seconds_bucket{"+labels+`,le="+Inf"} 2`+"\n")
+	is.Contains(body, "easymongo_operation_duration_seconds_count{"+labels+"} 2\n")
+}
@@ -0,0 +1,353 @@
+package easymongo
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// CommandStartedEvent describes a command sent to the server.
+type CommandStartedEvent struct {
+	RequestID    int64
+	ConnectionID string
+	Database     string
+	// Collection is the collection the command runs against (empty for commands such as ping)
+	Collection  string
+	CommandName string
+	// Command is the decoded command with every redacted field masked.
+	// It is nil if the command could not be decoded or if the driver withheld it (e.g. for authentication commands).
+	Command   bson.D
+	StartedAt time.Time
+}
+
+// CommandSucceededEvent describes a command which the server completed successfully.
+// The embedded CommandStartedEvent only has RequestID, ConnectionID and CommandName set if the
+// observer was added while the command was already running.
+type CommandSucceededEvent struct {
+	CommandStartedEvent
+	Duration time.Duration
+	// Reply is the decoded reply with every redacted field masked
+	Reply bson.D
+}
+
+// CommandFailedEvent describes a command which failed.
+// The embedded CommandStartedEvent only has RequestID, ConnectionID and CommandName set if the
+// observer was added while the command was already running.
+type CommandFailedEvent struct {
+	CommandStartedEvent
+	Duration time.Duration
+	Failure  string
+}
+
+// CommandObserver is notified of every command sent to the server. Observers are called synchronously
+// from the goroutine running the command, so they should be quick. Events are shared between observers
+// and must not be modified.
+type CommandObserver interface {
+	Started(ctx context.Context, e *CommandStartedEvent)
+	Succeeded(ctx context.Context, e *CommandSucceededEvent)
+	Failed(ctx context.Context, e *CommandFailedEvent)
+}
+
+// CommandObserverFuncs implements CommandObserver using (optional) functions.
+// e.g. conn.AddObserver(CommandObserverFuncs{OnFailed: func(ctx context.Context, e *CommandFailedEvent) { ... }})
+type CommandObserverFuncs struct {
+	OnStarted   func(ctx context.Context, e *CommandStartedEvent)
+	OnSucceeded func(ctx context.Context, e *CommandSucceededEvent)
+	OnFailed    func(ctx context.Context, e *CommandFailedEvent)
+}
+
+func (of CommandObserverFuncs) Started(ctx context.Context, e *CommandStartedEvent) {
+	if of.OnStarted != nil {
+		of.OnStarted(ctx, e)
+	}
+}
+
+func (of CommandObserverFuncs) Succeeded(ctx context.Context, e *CommandSucceededEvent) {
+	if of.OnSucceeded != nil {
+		of.OnSucceeded(ctx, e)
+	}
+}
+
+func (of CommandObserverFuncs) Failed(ctx context.Context, e *CommandFailedEvent) {
+	if of.OnFailed != nil {
+		of.OnFailed(ctx, e)
+	}
+}
+
+// observerEntry wraps a registered observer so it can be removed without comparing observers
+type observerEntry struct {
+	observer CommandObserver
+}
+
+// inFlightKey identifies a running command
+type inFlightKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// commandObservers holds the observers registered on a connection
+type commandObservers struct {
+	// lock serializes changes to entries
+	lock sync.Mutex
+	// entries holds a []*observerEntry, which is replaced (never modified) so it can be read without locking
+	entries atomic.Value
+	// inFlight maps an inFlightKey to the *CommandStartedEvent of a running command
+	inFlight sync.Map
+	// redactor masks fields before events are passed to observers. nil uses the default redactions.
+	redactor *Redactor
+	// removeDebug removes the debug logging observer (if debug is enabled)
+	removeDebug func()
+}
+
+// defaultRedactor is used when no redactions were added using ConnectionBuilder.Redact()
+var defaultRedactor = NewRedactor(defaultRedactions...)
+
+// Observe registers a CommandObserver which is notified of every command the connection sends.
+func (cb *ConnectionBuilder) Observe(observer CommandObserver) *ConnectionBuilder {
+	cb.connection.AddObserver(observer)
+	return cb
+}
+
+// Redact masks the provided field paths (in addition to password and pwd) before commands and replies
+// are passed to a CommandObserver (including the debug logger). See Redactor for how paths are matched.
+// e.g. ConnectWith(mongoURI).Redact("ssn", "billing.cardNumber").Debug().Connect()
+func (cb *ConnectionBuilder) Redact(paths ...string) *ConnectionBuilder {
+	if cb.connection.observers.redactor == nil {
+		cb.connection.observers.redactor = NewRedactor(defaultRedactions...)
+	}
+	cb.connection.observers.redactor.add(paths...)
+	return cb
+}
+
+// AddObserver registers a CommandObserver, which takes effect immediately without reconnecting.
+// Call the returned function to remove the observer.
+func (conn *Connection) AddObserver(observer CommandObserver) (remove func()) {
+	conn.observers.lock.Lock()
+	defer conn.observers.lock.Unlock()
+	return conn.observers.add(observer)
+}
+
+// add registers observer. lock must be held.
+func (co *commandObservers) add(observer CommandObserver) (remove func()) {
+	entry := &observerEntry{observer: observer}
+	current := co.list()
+	entries := make([]*observerEntry, 0, len(current)+1)
+	entries = append(entries, current...)
+	co.entries.Store(append(entries, entry))
+	return func() {
+		co.lock.Lock()
+		defer co.lock.Unlock()
+		current := co.list()
+		entries := make([]*observerEntry, 0, len(current))
+		for _, e := range current {
+			if e != entry {
+				entries = append(entries, e)
+			}
+		}
+		co.entries.Store(entries)
+	}
+}
+
+// list returns the registered observers
+func (co *commandObservers) list() []*observerEntry {
+	entries, _ := co.entries.Load().([]*observerEntry)
+	return entries
+}
+
+// redact masks doc using the configured redactions
+func (co *commandObservers) redact(doc bson.D) bson.D {
+	if co.redactor == nil {
+		return defaultRedactor.Redact(doc)
+	}
+	return co.redactor.Redact(doc)
+}
+
+// decode returns raw as a redacted bson.D (or nil if raw could not be decoded)
+func (co *commandObservers) decode(raw bson.Raw) bson.D {
+	if len(raw) == 0 {
+		return nil
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil
+	}
+	return co.redact(doc)
+}
+
+// commandCollection returns the collection a command runs against
+func commandCollection(commandName string, cmd bson.D) string {
+	if len(cmd) == 0 {
+		return ""
+	}
+	if coll, ok := cmd[0].Value.(string); ok && cmd[0].Key == commandName {
+		return coll
+	}
+	if commandName == "getMore" {
+		for _, elem := range cmd {
+			if coll, ok := elem.Value.(string); ok && elem.Key == "collection" {
+				return coll
+			}
+		}
+	}
+	return ""
+}
+
+// finished returns the event of the running command and stops tracking it
+func (co *commandObservers) finished(e event.CommandFinishedEvent) CommandStartedEvent {
+	key := inFlightKey{connectionID: e.ConnectionID, requestID: e.RequestID}
+	if started, found := co.inFlight.LoadAndDelete(key); found {
+		return *started.(*CommandStartedEvent)
+	}
+	return CommandStartedEvent{
+		RequestID:    e.RequestID,
+		ConnectionID: e.ConnectionID,
+		CommandName:  e.CommandName,
+	}
+}
+
+// commandMonitor returns the event.CommandMonitor installed on every client. It records the pool check out
+// wait, reports slow queries and dispatches events to the registered observers. Commands are only decoded while
+// observers are registered (or once a command turns out to be slow).
+func (conn *Connection) commandMonitor() *event.CommandMonitor {
+	co := &conn.observers
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			conn.pool.recordWait(ctx)
+			if conn.slowQueries != nil {
+				conn.slowQueries.started(e)
+			}
+			entries := co.list()
+			if len(entries) == 0 {
+				return
+			}
+			cmd := co.decode(e.Command)
+			started := &CommandStartedEvent{
+				RequestID:    e.RequestID,
+				ConnectionID: e.ConnectionID,
+				Database:     e.DatabaseName,
+				Collection:   commandCollection(e.CommandName, cmd),
+				CommandName:  e.CommandName,
+				Command:      cmd,
+				StartedAt:    time.Now(),
+			}
+			co.inFlight.Store(inFlightKey{connectionID: e.ConnectionID, requestID: e.RequestID}, started)
+			for _, entry := range entries {
+				entry.observer.Started(ctx, started)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			if conn.slowQueries != nil {
+				conn.slowQueries.succeeded(e)
+			}
+			started := co.finished(e.CommandFinishedEvent)
+			entries := co.list()
+			if len(entries) == 0 {
+				return
+			}
+			succeeded := &CommandSucceededEvent{
+				CommandStartedEvent: started,
+				Duration:            time.Duration(e.DurationNanos),
+				Reply:               co.decode(e.Reply),
+			}
+			for _, entry := range entries {
+				entry.observer.Succeeded(ctx, succeeded)
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			if conn.slowQueries != nil {
+				conn.slowQueries.failed(e)
+			}
+			started := co.finished(e.CommandFinishedEvent)
+			entries := co.list()
+			if len(entries) == 0 {
+				return
+			}
+			failed := &CommandFailedEvent{
+				CommandStartedEvent: started,
+				Duration:            time.Duration(e.DurationNanos),
+				Failure:             e.Failure,
+			}
+			for _, entry := range entries {
+				entry.observer.Failed(ctx, failed)
+			}
+		},
+	}
+}
+
+// debugObserver logs every command (and its reply) to the connection's Logger at the debug level
+type debugObserver struct {
+	conn *Connection
+}
+
+// extJSON renders doc as relaxed extended JSON for logging
+func extJSON(doc bson.D) string {
+	if doc == nil {
+		return "{}"
+	}
+	out, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return "<could not render as JSON: " + err.Error() + ">"
+	}
+	return string(out)
+}
+
+// fields returns the structured logging fields of a command
+func (e *CommandStartedEvent) fields() Fields {
+	return Fields{
+		"db":         e.Database,
+		"collection": e.Collection,
+		"command":    e.CommandName,
+		"requestId":  e.RequestID,
+	}
+}
+
+func (do debugObserver) Started(_ context.Context, e *CommandStartedEvent) {
+	do.conn.logger().WithFields(e.fields()).
+		Debugf("Command executing against DB: '%s' Command: %s", e.Database, extJSON(e.Command))
+}
+
+func (do debugObserver) Succeeded(_ context.Context, e *CommandSucceededEvent) {
+	// There's more in the reply than just the data that was returned if it's a cursor.
+	// Only log the first batch in that case.
+	result := extJSON(e.Reply)
+	if cursor, ok := e.Reply.Map()["cursor"].(bson.D); ok {
+		if batch, ok := cursor.Map()["firstBatch"]; ok {
+			result = extJSON(bson.D{{Key: "firstBatch", Value: batch}})
+		}
+	}
+	do.conn.logger().WithFields(e.fields()).WithField("duration", e.Duration).
+		Debugf("DB execution success: Result: %s", result)
+}
+
+func (do debugObserver) Failed(_ context.Context, e *CommandFailedEvent) {
+	do.conn.logger().WithFields(e.fields()).WithField("duration", e.Duration).
+		Errorf("DB command failed: %s", e.Failure)
+}
+
+// enableDebugObserver registers the debug logging observer (should it not be registered already)
+func (conn *Connection) enableDebugObserver() {
+	if conn.log == nil {
+		conn.SetLogger(NewDefaultLogger())
+		conn.logger().Debugf("No logger was set using Connection.SetLogger(). Initialized a new one for debugging.")
+	}
+	conn.observers.lock.Lock()
+	defer conn.observers.lock.Unlock()
+	if conn.observers.removeDebug == nil {
+		conn.observers.removeDebug = conn.observers.add(debugObserver{conn: conn})
+	}
+}
+
+// disableDebugObserver removes the debug logging observer (should it be registered)
+func (conn *Connection) disableDebugObserver() {
+	conn.observers.lock.Lock()
+	remove := conn.observers.removeDebug
+	conn.observers.removeDebug = nil
+	conn.observers.lock.Unlock()
+	if remove != nil {
+		remove()
+	}
+}
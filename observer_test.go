@@ -0,0 +1,57 @@
+package easymongo_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCommandObserver(t *testing.T) {
+	setup(t)
+	is := assert.New(t)
+	var lock sync.Mutex
+	var started []*easymongo.CommandStartedEvent
+	var succeeded []*easymongo.CommandSucceededEvent
+	remove := conn.AddObserver(easymongo.CommandObserverFuncs{
+		OnStarted: func(_ context.Context, e *easymongo.CommandStartedEvent) {
+			lock.Lock()
+			defer lock.Unlock()
+			started = append(started, e)
+		},
+		OnSucceeded: func(_ context.Context, e *easymongo.CommandSucceededEvent) {
+			lock.Lock()
+			defer lock.Unlock()
+			succeeded = append(succeeded, e)
+		},
+	})
+
+	coll := conn.Database("batman_archive").C("secret_identities")
+	_, err := coll.Insert().One(bson.M{"_id": primitive.NewObjectID(), "name": "Bruce Wayne", "password": "alfred"})
+	is.NoError(err)
+
+	lock.Lock()
+	is.Len(started, 1)
+	is.Len(succeeded, 1)
+	if len(started) == 1 && len(succeeded) == 1 {
+		is.Equal("insert", started[0].CommandName)
+		is.Equal("batman_archive", started[0].Database)
+		is.Equal("secret_identities", started[0].Collection)
+		doc := started[0].Command.Map()["documents"].(bson.A)[0].(bson.D).Map()
+		is.Equal(easymongo.RedactedValue, doc["password"], "Passwords should be redacted by default")
+		is.Equal(started[0].RequestID, succeeded[0].RequestID)
+		is.Equal("secret_identities", succeeded[0].Collection, "Succeeded events should carry the started command")
+	}
+	lock.Unlock()
+
+	remove()
+	_, err = coll.Insert().One(bson.M{"_id": primitive.NewObjectID(), "name": "Dick Grayson"})
+	is.NoError(err)
+	lock.Lock()
+	is.Len(started, 1, "A removed observer should not be notified")
+	lock.Unlock()
+}
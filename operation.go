@@ -0,0 +1,166 @@
+package easymongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// operation describes a terminal call made against a collection (e.g. FindQuery.All).
+type operation struct {
+	// name identifies the easymongo method being run
+	name string
+	// command is the mongo command the method runs
+	command string
+	// write is true if the operation modifies data
+	write bool
+	// retryable is true if the operation is safe to run again should it fail with a transient error.
+	// Reads are idempotent, while single document writes are only retried if they were not applied.
+	retryable bool
+}
+
+var (
+	opFindOne             = operation{name: "FindQuery.One", command: "find", retryable: true}
+	opFindAll             = operation{name: "FindQuery.All", command: "find", retryable: true}
+	opFindCursor          = operation{name: "FindQuery.Cursor", command: "find", retryable: true}
+	opFindCount           = operation{name: "FindQuery.Count", command: "aggregate", retryable: true}
+	opFindDistinct        = operation{name: "FindQuery.Distinct", command: "aggregate", retryable: true}
+	opFindDistinctStrings = operation{name: "FindQuery.DistinctStrings", command: "distinct", retryable: true}
+	opFindAndUpdate       = operation{name: "FindAndQuery.Update", command: "findAndModify", write: true, retryable: true}
+	opFindAndReplace      = operation{name: "FindAndQuery.Replace", command: "findAndModify", write: true, retryable: true}
+	opFindAndDelete       = operation{name: "FindAndQuery.Delete", command: "findAndModify", write: true, retryable: true}
+	opInsertOne           = operation{name: "InsertQuery.One", command: "insert", write: true, retryable: true}
+	opInsertMany          = operation{name: "InsertQuery.Many", command: "insert", write: true}
+	opUpdateOne           = operation{name: "UpdateQuery.One", command: "update", write: true, retryable: true}
+	opUpdateAll           = operation{name: "UpdateQuery.All", command: "update", write: true}
+	opDeleteOne           = operation{name: "DeleteQuery.One", command: "delete", write: true, retryable: true}
+	opDeleteMany          = operation{name: "DeleteQuery.Many", command: "delete", write: true}
+	opReplaceOne          = operation{name: "ReplaceQuery.One", command: "update", write: true, retryable: true}
+	opAggregateOne        = operation{name: "AggregationQuery.One", command: "aggregate", retryable: true}
+	opAggregateAll        = operation{name: "AggregationQuery.All", command: "aggregate", retryable: true}
+	opAggregateCursor     = operation{name: "AggregationQuery.Cursor", command: "aggregate", retryable: true}
+	opEstimatedCount      = operation{name: "Collection.EstimatedCount", command: "count", retryable: true}
+	opDropCollection      = operation{name: "Collection.Drop", command: "drop", write: true}
+	opEnsureIndex         = operation{name: "Index.Ensure", command: "createIndexes", write: true, retryable: true}
+)
+
+// OpError describes the operation which returned an error. Every error returned by an operation is wrapped in
+// an OpError, so errors.Is(err, ErrNoDocuments) and errors.As(err, &mongoErr) keep working.
+// e.g. var opErr easymongo.OpError; if errors.As(err, &opErr) { log.Printf("%s failed on %s", opErr.Op, opErr.Collection) }
+type OpError struct {
+	// Op is the method which failed (e.g. "FindQuery.One")
+	Op string
+	// DB is the name of the database the operation ran against
+	DB string
+	// Collection is the name of the collection the operation ran against
+	Collection string
+	// FilterShape is the filter (or pipeline) with every value replaced by "?" (e.g. {"name":"?"}).
+	// Map keys are sorted, so the same filter always has the same shape. It is empty if the operation has no filter.
+	FilterShape string
+	// Duration is how long the operation took, including retries
+	Duration time.Duration
+	// Attempt is the number of attempts made (0 if the operation was refused, e.g. with ErrCircuitOpen)
+	Attempt int
+	// Err is the error returned by the operation
+	Err error
+}
+
+func (oe OpError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s on %s.%s", oe.Op, oe.DB, oe.Collection)
+	if oe.FilterShape != "" {
+		fmt.Fprintf(&b, " with filter %s", oe.FilterShape)
+	}
+	fmt.Fprintf(&b, " failed after %v", oe.Duration)
+	if oe.Attempt > 1 {
+		fmt.Fprintf(&b, " and %d attempts", oe.Attempt)
+	}
+	fmt.Fprintf(&b, ": %v", oe.Err)
+	return b.String()
+}
+
+// Unwrap returns the error returned by the operation
+func (oe OpError) Unwrap() error {
+	return oe.Err
+}
+
+// run executes fn as a tracked operation against the query's collection using the query's context.
+// Every terminal query method (e.g. FindQuery.All, UpdateQuery.One) funnels through run.
+func (q *Query) run(op operation, fn func(ctx context.Context) error) error {
+	ctx, cancel := q.getContext()
+	defer cancel()
+	return q.collection.run(ctx, op, q, fn)
+}
+
+// run executes fn as a tracked operation against the collection. The operation is refused
+// with ErrConnectionClosed once the connection has been closed, and Connection.Close waits on
+// operations that are still running. Each operation is recorded in Metrics() and logged at the debug level
+// with db, collection, operation and duration fields. Should a Tracer be set, the operation is run in a span
+// described by the query (which is nil for collection level operations). Transient failures are retried
+// according to the RetryPolicy. Should the collection (or query) have been given conflicting flags, the operation
+// fails with the validation error without running. Should a CircuitBreaker be enabled, the operation fails with ErrCircuitOpen
+// while the circuit is open. Errors are mapped to easymongo errors (see MongoErr), with unique index violations
+// reported as a DuplicateKeyError (or DuplicateKeyErrors for InsertQuery.Many), and wrapped in an OpError.
+func (c *Collection) run(ctx context.Context, op operation, q *Query, fn func(ctx context.Context) error) error {
+	conn := c.Connection()
+	start := time.Now()
+	var filter interface{}
+	if q != nil {
+		filter = q.filter
+	}
+	ctx, span := c.startSpan(ctx, op, filter)
+	var attempts int
+	err := c.flagsError()
+	if err == nil {
+		err = conn.breaker.allow(ctx, conn)
+	}
+	if err == nil {
+		err = c.database.track(func() (err error) {
+			attempts, err = c.attempt(ctx, op, q, fn)
+			return err
+		})
+		conn.breaker.record(conn, err)
+	}
+	err = duplicateKeyErr(op, classifyErr(err))
+	duration := time.Since(start)
+	if span != nil && attempts > 1 {
+		span.SetAttributes(Attribute{Key: AttributeAttempts, Value: attempts})
+	}
+	endSpan(span, err)
+	conn.metrics.record(metricsKey{db: c.database.dbName, collection: c.collectionName, operation: op.name}, duration, err)
+	if conn.log == nil {
+		return c.opError(op, filter, duration, attempts, err)
+	}
+	log := conn.logger().WithFields(Fields{
+		"db":         c.database.dbName,
+		"collection": c.collectionName,
+		"operation":  op.name,
+		"command":    op.command,
+		"duration":   duration,
+		"attempts":   attempts,
+	})
+	if err != nil {
+		log.WithField("error", err).Debugf("%s failed: %v", op.name, err)
+	} else {
+		log.Debugf("%s succeeded", op.name)
+	}
+	return c.opError(op, filter, duration, attempts, err)
+}
+
+// opError wraps err in an OpError describing the operation (unless err is nil)
+func (c *Collection) opError(op operation, filter interface{}, duration time.Duration, attempts int, err error) error {
+	if err == nil {
+		return nil
+	}
+	shape, _ := filterShape(filter)
+	return OpError{
+		Op:          op.name,
+		DB:          c.database.dbName,
+		Collection:  c.collectionName,
+		FilterShape: shape,
+		Duration:    duration,
+		Attempt:     attempts,
+		Err:         err,
+	}
+}
@@ -0,0 +1,63 @@
+package easymongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestOpError(t *testing.T) {
+	is := assert.New(t)
+	// Nothing is listening on this port, so every operation fails
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		DefaultQueryTimeout(50 * time.Millisecond).
+		RetryPolicy(easymongo.RetryPolicy{MaxAttempts: 1}).
+		Connect()
+	is.NoError(err)
+	coll := tmpConn.Database("batman_archive").C("enemies")
+
+	var e enemy
+	err = coll.Find(bson.D{{Key: "name", Value: "The Joker"}, {Key: "evilness", Value: bson.M{"$gt": 90}}}).One(&e)
+	var opErr easymongo.OpError
+	if is.True(errors.As(err, &opErr), "Errors should be wrapped in an OpError") {
+		is.Equal("FindQuery.One", opErr.Op)
+		is.Equal("batman_archive", opErr.DB)
+		is.Equal("enemies", opErr.Collection)
+		is.Equal(`{"name":"?","evilness":{"$gt":"?"}}`, opErr.FilterShape, "Filter values should be redacted")
+		is.Equal(1, opErr.Attempt)
+		is.Greater(int64(opErr.Duration), int64(0))
+		is.NotContains(opErr.Error(), "The Joker")
+		is.Contains(opErr.Error(), "FindQuery.One on batman_archive.enemies")
+	}
+	is.ErrorIs(err, easymongo.ErrTimeoutOccurred, "The wrapped error should still match")
+
+	// Maps are rendered with sorted keys, so the same failure always produces the same message
+	mapFilter := bson.M{"timesFought": bson.M{"$lt": 3}, "name": "Bane", "evilness": bson.M{"$gt": 90}, "deceased": false}
+	err = coll.Find(mapFilter).One(&e)
+	if is.True(errors.As(err, &opErr)) {
+		is.Equal(`{"deceased":"?","evilness":{"$gt":"?"},"name":"?","timesFought":{"$lt":"?"}}`, opErr.FilterShape)
+		for i := 0; i < 5; i++ {
+			var again easymongo.OpError
+			if is.True(errors.As(coll.Find(mapFilter).One(&e), &again)) {
+				is.Equal(opErr.FilterShape, again.FilterShape, "The filter shape should be stable between runs")
+			}
+		}
+	}
+
+	is.NoError(tmpConn.Close(context.Background()))
+	_, err = coll.Insert().One(&enemy{Name: "The Joker"})
+	is.ErrorIs(err, easymongo.ErrConnectionClosed)
+	if is.True(errors.As(err, &opErr)) {
+		is.Equal("InsertQuery.One", opErr.Op)
+		is.Empty(opErr.FilterShape, "Inserts have no filter")
+	}
+
+	wrapped := easymongo.OpError{Op: "FindQuery.One", Err: easymongo.NewMongoErr(errors.New("E11000"))}
+	is.Equal(wrapped.Err, errors.Unwrap(wrapped))
+}
@@ -0,0 +1,14 @@
+module github.com/tophergopher/easymongo/otel
+
+go 1.16
+
+require (
+	github.com/stretchr/testify v1.7.0
+	github.com/tophergopher/easymongo v0.1.0
+	go.mongodb.org/mongo-driver v1.7.1
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+)
+
+replace github.com/tophergopher/easymongo => ../
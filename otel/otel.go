@@ -0,0 +1,91 @@
+// Package otel adapts an OpenTelemetry trace.Tracer to the easymongo.Tracer interface.
+// It is a separate module, so easymongo itself does not depend on OpenTelemetry.
+// e.g. easymongo.ConnectWith(mongoURI).Tracer(otel.NewTracer(otel.DefaultTracer())).Connect()
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tophergopher/easymongo"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name DefaultTracer is registered under
+const InstrumentationName = "github.com/tophergopher/easymongo"
+
+// DefaultTracer returns a trace.Tracer from the global OpenTelemetry TracerProvider.
+func DefaultTracer() trace.Tracer {
+	return otelapi.Tracer(InstrumentationName)
+}
+
+// Tracer implements easymongo.Tracer using an OpenTelemetry trace.Tracer
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns an easymongo.Tracer which starts client spans using tracer.
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{tracer: tracer}
+}
+
+// StartSpan starts a client span which is a child of any span in ctx
+func (t *Tracer) StartSpan(ctx context.Context, name string, attributes ...easymongo.Attribute) (context.Context, easymongo.Span) {
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convert(attributes)...),
+	)
+	return ctx, &Span{span: span}
+}
+
+// Span implements easymongo.Span using an OpenTelemetry trace.Span
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attributes ...easymongo.Attribute) {
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+// RecordError records err on the span and sets its status to Error
+func (s *Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End completes the span
+func (s *Span) End() {
+	s.span.End()
+}
+
+// convert returns attributes as OpenTelemetry attributes
+func convert(attributes []easymongo.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attributes))
+	for _, attr := range attributes {
+		kvs = append(kvs, keyValue(attr))
+	}
+	return kvs
+}
+
+// keyValue converts a single attribute, falling back to its string representation
+func keyValue(attr easymongo.Attribute) attribute.KeyValue {
+	switch v := attr.Value.(type) {
+	case string:
+		return attribute.String(attr.Key, v)
+	case bool:
+		return attribute.Bool(attr.Key, v)
+	case int:
+		return attribute.Int(attr.Key, v)
+	case int64:
+		return attribute.Int64(attr.Key, v)
+	case float64:
+		return attribute.Float64(attr.Key, v)
+	case fmt.Stringer:
+		return attribute.String(attr.Key, v.String())
+	}
+	return attribute.String(attr.Key, fmt.Sprint(attr.Value))
+}
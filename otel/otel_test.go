@@ -0,0 +1,65 @@
+package otel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"github.com/tophergopher/easymongo/otel"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	is := assert.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+
+	// Nothing is listening on this port, so the operation fails once its timeout passes
+	conn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		DefaultQueryTimeout(50 * time.Millisecond).
+		Tracer(otel.NewTracer(provider.Tracer(otel.InstrumentationName))).
+		Connect()
+	is.NoError(err)
+	defer conn.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ctx, parent := provider.Tracer("test").Start(ctx, "handler")
+	var result bson.M
+	err = conn.Database("batman_archive").C("enemies").Find(bson.M{"name": "The Joker"}).WithContext(ctx).One(&result)
+	is.Error(err)
+	parent.End()
+
+	spans := recorder.Ended()
+	if !is.Len(spans, 2, "The operation and its parent should have been exported") {
+		return
+	}
+	span := spans[0]
+	is.Equal(parent.SpanContext().TraceID(), span.SpanContext().TraceID(), "The span should be in the trace of the query context")
+	is.Equal(parent.SpanContext().SpanID(), span.Parent().SpanID(), "The span should be a child of the span in the query context")
+	is.Equal(trace.SpanKindClient, span.SpanKind())
+	is.Equal(codes.Error, span.Status().Code, "The error should be recorded")
+
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	for key, expected := range map[string]string{
+		easymongo.AttributeDBSystem:     "mongodb",
+		easymongo.AttributeDBName:       "batman_archive",
+		easymongo.AttributeDBOperation:  "find",
+		easymongo.AttributeDBCollection: "enemies",
+		easymongo.AttributeDBStatement:  `{"name":"?"}`,
+	} {
+		is.Equal(expected, attributes[attribute.Key(key)].AsString(), key)
+	}
+}
@@ -0,0 +1,180 @@
+package easymongo
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MaxPoolSize limits the number of connections the driver keeps open to each server (default 100).
+// Operations block waiting for a connection once the limit is reached. Use 0 for no limit.
+func (cb *ConnectionBuilder) MaxPoolSize(size uint64) *ConnectionBuilder {
+	cb.connection.mongoOptions.maxPoolSize = &size
+	return cb
+}
+
+// MinPoolSize sets the number of idle connections the driver keeps open to each server (default 0).
+func (cb *ConnectionBuilder) MinPoolSize(size uint64) *ConnectionBuilder {
+	cb.connection.mongoOptions.minPoolSize = &size
+	return cb
+}
+
+// MaxConnIdleTime sets how long a connection may sit idle in the pool before it is closed (default unlimited).
+func (cb *ConnectionBuilder) MaxConnIdleTime(d time.Duration) *ConnectionBuilder {
+	cb.connection.mongoOptions.maxConnIdleTime = &d
+	return cb
+}
+
+// MaxConnecting limits the number of connections each server's pool may be establishing at once (the driver
+// defaults to 2 from v1.8). It requires mongo-driver v1.8 or later: the v1.7 driver easymongo currently builds
+// against has no such option, so the setting is ignored (and a warning is logged on Connect) until the driver is upgraded.
+func (cb *ConnectionBuilder) MaxConnecting(n uint64) *ConnectionBuilder {
+	cb.connection.mongoOptions.maxConnecting = &n
+	return cb
+}
+
+// HeartbeatInterval sets how often the driver checks on the state of each server (default 10 seconds).
+func (cb *ConnectionBuilder) HeartbeatInterval(d time.Duration) *ConnectionBuilder {
+	cb.connection.mongoOptions.heartbeatInterval = &d
+	return cb
+}
+
+// applyPool sets the configured pool options on opts
+func (mo *MongoConnectOptions) applyPool(opts *options.ClientOptions) {
+	if mo.maxPoolSize != nil {
+		opts.SetMaxPoolSize(*mo.maxPoolSize)
+	}
+	if mo.minPoolSize != nil {
+		opts.SetMinPoolSize(*mo.minPoolSize)
+	}
+	if mo.maxConnIdleTime != nil {
+		opts.SetMaxConnIdleTime(*mo.maxConnIdleTime)
+	}
+	if mo.heartbeatInterval != nil {
+		opts.SetHeartbeatInterval(*mo.heartbeatInterval)
+	}
+}
+
+// PoolStats is a snapshot of the connection pool counters for a connection.
+// The counters are kept across clients, so they remain accurate when the client is swapped (e.g. when credentials rotate).
+type PoolStats struct {
+	// Open is the number of connections currently open
+	Open int64
+	// InUse is the number of connections currently checked out of the pool
+	InUse int64
+	// Idle is the number of open connections waiting in the pool
+	Idle int64
+	// CheckOuts is the total number of successful connection check outs
+	CheckOuts uint64
+	// CheckOutFailures is the total number of connection check outs which failed (e.g. the wait for a connection timed out)
+	CheckOutFailures uint64
+	// Cleared is the number of times a pool was cleared (which occurs when a server is marked unknown)
+	Cleared uint64
+	// CheckOutWait is the total time operations spent waiting on server selection and a connection check out
+	CheckOutWait time.Duration
+	// MaxCheckOutWait is the longest time a single operation spent waiting on server selection and a connection check out
+	MaxCheckOutWait time.Duration
+	// Waits is the number of operations CheckOutWait was measured across
+	Waits uint64
+}
+
+// AverageCheckOutWait returns the mean time operations spent waiting for a connection.
+func (ps PoolStats) AverageCheckOutWait() time.Duration {
+	if ps.Waits == 0 {
+		return 0
+	}
+	return ps.CheckOutWait / time.Duration(ps.Waits)
+}
+
+// poolMonitor keeps live counters of pool events. All fields are accessed atomically.
+type poolMonitor struct {
+	open             int64
+	inUse            int64
+	checkOuts        uint64
+	checkOutFailures uint64
+	cleared          uint64
+	// waitNanos, maxWaitNanos and waits track how long operations wait before their first command is sent
+	waitNanos    int64
+	maxWaitNanos int64
+	waits        uint64
+}
+
+// PoolStats returns a snapshot of the connection pool counters.
+// The counters are not kept for a client passed in using FromMongoDriverClient().
+func (conn *Connection) PoolStats() PoolStats {
+	pm := &conn.pool
+	stats := PoolStats{
+		Open:             atomic.LoadInt64(&pm.open),
+		InUse:            atomic.LoadInt64(&pm.inUse),
+		CheckOuts:        atomic.LoadUint64(&pm.checkOuts),
+		CheckOutFailures: atomic.LoadUint64(&pm.checkOutFailures),
+		Cleared:          atomic.LoadUint64(&pm.cleared),
+		CheckOutWait:     time.Duration(atomic.LoadInt64(&pm.waitNanos)),
+		MaxCheckOutWait:  time.Duration(atomic.LoadInt64(&pm.maxWaitNanos)),
+		Waits:            atomic.LoadUint64(&pm.waits),
+	}
+	stats.Idle = stats.Open - stats.InUse
+	if stats.Idle < 0 {
+		stats.Idle = 0
+	}
+	return stats
+}
+
+// poolEventMonitor returns the event.PoolMonitor which feeds the counters
+func (pm *poolMonitor) poolEventMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.ConnectionCreated:
+				atomic.AddInt64(&pm.open, 1)
+			case event.ConnectionClosed:
+				atomic.AddInt64(&pm.open, -1)
+			case event.GetSucceeded:
+				atomic.AddUint64(&pm.checkOuts, 1)
+				atomic.AddInt64(&pm.inUse, 1)
+			case event.GetFailed:
+				atomic.AddUint64(&pm.checkOutFailures, 1)
+			case event.ConnectionReturned:
+				atomic.AddInt64(&pm.inUse, -1)
+			case event.PoolCleared:
+				atomic.AddUint64(&pm.cleared, 1)
+			}
+		},
+	}
+}
+
+// operationStartKey is the context key holding the *operationStart of a running operation
+type operationStartKey struct{}
+
+// operationStart records when an operation began, so the time spent waiting for a connection can be measured
+type operationStart struct {
+	at time.Time
+	// measured is set once the first command of the operation has been sent
+	measured int32
+}
+
+// withOperationStart marks ctx with the time an operation began
+func withOperationStart(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operationStartKey{}, &operationStart{at: time.Now()})
+}
+
+// recordWait measures the time between an operation starting and its first command being sent.
+// The driver (as of v1.7) does not emit a check out started event, so this also includes server selection.
+func (pm *poolMonitor) recordWait(ctx context.Context) {
+	start, ok := ctx.Value(operationStartKey{}).(*operationStart)
+	if !ok || !atomic.CompareAndSwapInt32(&start.measured, 0, 1) {
+		return
+	}
+	wait := int64(time.Since(start.at))
+	atomic.AddInt64(&pm.waitNanos, wait)
+	atomic.AddUint64(&pm.waits, 1)
+	for {
+		max := atomic.LoadInt64(&pm.maxWaitNanos)
+		if wait <= max || atomic.CompareAndSwapInt64(&pm.maxWaitNanos, max, wait) {
+			return
+		}
+	}
+}
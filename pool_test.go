@@ -0,0 +1,71 @@
+package easymongo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestPoolStats(t *testing.T) {
+	is := assert.New(t)
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		MaxPoolSize(10).
+		MinPoolSize(0).
+		MaxConnIdleTime(time.Minute).
+		HeartbeatInterval(time.Second).
+		DefaultOperationTimeout(100 * time.Millisecond).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+
+	// Nothing is listening, so server selection fails before a connection is ever checked out
+	is.Error(tmpConn.Ping())
+	stats := tmpConn.PoolStats()
+	is.Equal(int64(0), stats.Open)
+	is.Equal(int64(0), stats.InUse)
+	is.Equal(uint64(0), stats.CheckOuts)
+	is.Equal(time.Duration(0), stats.AverageCheckOutWait())
+
+	// Feed the monitor the events the driver emits around a check out. The driver clears the pool
+	// itself whenever its heartbeat fails, so Cleared is compared against its earlier value.
+	cleared := stats.Cleared
+	for _, eventType := range []string{event.ConnectionCreated, event.ConnectionCreated, event.GetSucceeded} {
+		tmpConn.PoolEvent(&event.PoolEvent{Type: eventType, Address: "127.0.0.1:1"})
+	}
+	stats = tmpConn.PoolStats()
+	is.Equal(int64(2), stats.Open)
+	is.Equal(int64(1), stats.InUse)
+	is.Equal(int64(1), stats.Idle)
+	is.Equal(uint64(1), stats.CheckOuts)
+
+	for _, eventType := range []string{event.ConnectionReturned, event.GetFailed, event.PoolCleared, event.ConnectionClosed} {
+		tmpConn.PoolEvent(&event.PoolEvent{Type: eventType, Address: "127.0.0.1:1"})
+	}
+	stats = tmpConn.PoolStats()
+	is.Equal(int64(1), stats.Open)
+	is.Equal(int64(0), stats.InUse)
+	is.Equal(int64(1), stats.Idle)
+	is.Equal(uint64(1), stats.CheckOuts)
+	is.Equal(uint64(1), stats.CheckOutFailures)
+	is.GreaterOrEqual(stats.Cleared, cleared+1)
+}
+
+func TestMaxConnecting(t *testing.T) {
+	is := assert.New(t)
+	log := &twoMethodLogger{}
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		Logger(log).
+		MaxConnecting(4).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+	is.Len(log.lines, 1)
+	is.Contains(strings.Join(log.lines, "\n"), "MaxConnecting requires mongo-driver v1.8", "The ignored setting should be reported")
+}
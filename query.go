@@ -6,6 +6,9 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Query is used for creating
@@ -30,6 +33,10 @@ type Query struct {
 	timeout     *time.Duration
 	collection  *Collection
 	providedCtx *context.Context
+	// retryPolicy overrides the connection's RetryPolicy
+	retryPolicy *RetryPolicy
+	// errorIfNotFound overrides the connection's StrictNotFound setting
+	errorIfNotFound *bool
 }
 
 // type QueryI interface {
@@ -100,6 +107,50 @@ func (q *Query) setCollation(c *options.Collation) *Query {
 	return q
 }
 
+// setReadPreference overrides the read preference of the collection for the query
+func (q *Query) setReadPreference(rp *readpref.ReadPref) *Query {
+	q.collection = q.collection.WithReadPreference(rp)
+	return q
+}
+
+// setReadConcern overrides the read concern of the collection for the query
+func (q *Query) setReadConcern(rc *readconcern.ReadConcern) *Query {
+	q.collection = q.collection.WithReadConcern(rc)
+	return q
+}
+
+// setWriteConcern overrides the write concern of the collection for the query
+func (q *Query) setWriteConcern(wc *writeconcern.WriteConcern) *Query {
+	q.collection = q.collection.WithWriteConcern(wc)
+	return q
+}
+
+// setFlags overrides the read concern, read preference and write concern of the collection
+// for the query using the settings in flags. Conflicting flags fail the query when it is run.
+func (q *Query) setFlags(flags ConnectionFlag) *Query {
+	q.collection = q.collection.WithFlags(flags)
+	return q
+}
+
+// setErrorIfNotFound controls whether ErrNoDocuments is returned when nothing matches the filter
+func (q *Query) setErrorIfNotFound(errorIfNotFound bool) *Query {
+	q.errorIfNotFound = &errorIfNotFound
+	return q
+}
+
+// notFoundErr returns ErrNoDocuments if no documents were matched (or upserted) and the query
+// (or the connection, see StrictNotFound) asks for it
+func (q *Query) notFoundErr(matched int64) error {
+	errorIfNotFound := q.collection.Connection().mongoOptions.strictNotFound
+	if q.errorIfNotFound != nil {
+		errorIfNotFound = *q.errorIfNotFound
+	}
+	if errorIfNotFound && matched == 0 {
+		return ErrNoDocuments
+	}
+	return nil
+}
+
 // setContext allows one to override the implied context that is typically created
 // at query time and instead will consume this.
 func (q *Query) setContext(ctx *context.Context) *Query {
@@ -112,10 +163,10 @@ func (q *Query) setContext(ctx *context.Context) *Query {
 // getContext should be called after the query has been constructed (thus the private specification).
 func (q *Query) getContext() (context.Context, context.CancelFunc) {
 	if q.providedCtx != nil {
-		return *q.providedCtx, noopCancelFunc
+		return q.collection.database.bindSession(*q.providedCtx), noopCancelFunc
 	}
 	if q.timeout != nil {
-		return context.WithTimeout(context.Background(), *q.timeout)
+		return context.WithTimeout(q.collection.database.baseCtx(), *q.timeout)
 	}
 	return q.collection.defaultQueryCtx()
 }
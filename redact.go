@@ -0,0 +1,108 @@
+package easymongo
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RedactedValue replaces the value of any field matched by a Redactor
+const RedactedValue = "[REDACTED]"
+
+// defaultRedactions are always masked before a command is passed to a CommandObserver
+var defaultRedactions = []string{"password", "pwd"}
+
+// Redactor masks the values of field paths within a document.
+// A path matches the trailing fields of a field's full path, so "ssn" masks every field named ssn
+// (no matter how deeply it is nested) while "address.zip" only masks zip when it is nested in address.
+// Array indices are not part of a path, so "documents.ssn" matches documents[0].ssn.
+type Redactor struct {
+	paths [][]string
+}
+
+// NewRedactor returns a Redactor which masks the provided field paths.
+// e.g. NewRedactor("password", "ssn", "billing.cardNumber")
+func NewRedactor(paths ...string) *Redactor {
+	r := &Redactor{}
+	r.add(paths...)
+	return r
+}
+
+// add appends paths to the set the Redactor masks
+func (r *Redactor) add(paths ...string) {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		r.paths = append(r.paths, strings.Split(path, "."))
+	}
+}
+
+// Redact returns a copy of doc with the value of every matched field replaced by RedactedValue.
+// doc itself is left untouched.
+func (r *Redactor) Redact(doc bson.D) bson.D {
+	if r == nil || len(r.paths) == 0 || doc == nil {
+		return doc
+	}
+	return r.redactValue(nil, doc).(bson.D)
+}
+
+// redactValue walks v (found at path), returning a redacted copy
+func (r *Redactor) redactValue(path []string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case primitive.D:
+		out := make(primitive.D, len(val))
+		for i, elem := range val {
+			out[i] = r.redactElement(path, elem.Key, elem.Value)
+		}
+		return out
+	case primitive.M:
+		out := make(primitive.M, len(val))
+		for k, elem := range val {
+			e := r.redactElement(path, k, elem)
+			out[k] = e.Value
+		}
+		return out
+	case primitive.A:
+		out := make(primitive.A, len(val))
+		for i, elem := range val {
+			out[i] = r.redactValue(path, elem)
+		}
+		return out
+	}
+	return v
+}
+
+// redactElement masks value if the key (nested at path) is matched
+func (r *Redactor) redactElement(path []string, key string, value interface{}) primitive.E {
+	// Copy the path so sibling elements don't share a backing array
+	fieldPath := make([]string, 0, len(path)+1)
+	fieldPath = append(fieldPath, path...)
+	fieldPath = append(fieldPath, strings.Split(key, ".")...)
+	if r.matches(fieldPath) {
+		return primitive.E{Key: key, Value: RedactedValue}
+	}
+	return primitive.E{Key: key, Value: r.redactValue(fieldPath, value)}
+}
+
+// matches returns true if any of the redacted paths match the trailing fields of fieldPath
+func (r *Redactor) matches(fieldPath []string) bool {
+	for _, path := range r.paths {
+		if len(path) > len(fieldPath) {
+			continue
+		}
+		tail := fieldPath[len(fieldPath)-len(path):]
+		matched := true
+		for i := range path {
+			if path[i] != tail[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
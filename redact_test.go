@@ -0,0 +1,43 @@
+package easymongo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRedactor(t *testing.T) {
+	is := assert.New(t)
+	r := easymongo.NewRedactor("ssn", "billing.cardNumber")
+	cmd := bson.D{
+		{Key: "insert", Value: "villains"},
+		{Key: "documents", Value: bson.A{
+			bson.D{
+				{Key: "name", Value: "The Riddler"},
+				{Key: "ssn", Value: "123-45-6789"},
+				{Key: "billing", Value: bson.D{{Key: "cardNumber", Value: "4111"}, {Key: "zip", Value: "10001"}}},
+				{Key: "cardNumber", Value: "not nested in billing"},
+			},
+		}},
+		{Key: "updates", Value: bson.A{
+			bson.D{{Key: "u", Value: bson.D{{Key: "$set", Value: bson.D{{Key: "billing.cardNumber", Value: "5555"}}}}}},
+		}},
+	}
+	redacted := r.Redact(cmd)
+
+	doc := redacted.Map()["documents"].(bson.A)[0].(bson.D).Map()
+	is.Equal("The Riddler", doc["name"])
+	is.Equal(easymongo.RedactedValue, doc["ssn"], "A single field name should be masked at any depth")
+	billing := doc["billing"].(bson.D).Map()
+	is.Equal(easymongo.RedactedValue, billing["cardNumber"], "A path should be matched against the trailing fields")
+	is.Equal("10001", billing["zip"])
+	is.Equal("not nested in billing", doc["cardNumber"], "A path should not match a field outside of its parent")
+
+	set := redacted.Map()["updates"].(bson.A)[0].(bson.D).Map()["u"].(bson.D).Map()["$set"].(bson.D).Map()
+	is.Equal(easymongo.RedactedValue, set["billing.cardNumber"], "Dotted keys should be matched like nested fields")
+
+	original := cmd.Map()["documents"].(bson.A)[0].(bson.D).Map()
+	is.Equal("123-45-6789", original["ssn"], "The original document should not be modified")
+}
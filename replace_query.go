@@ -1,6 +1,14 @@
 package easymongo
 
-import "go.mongodb.org/mongo-driver/mongo/options"
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
 
 // ReplaceQuery is a helper for replacement query actions and options.
 type ReplaceQuery struct {
@@ -18,20 +26,72 @@ func (c *Collection) Replace(filter interface{}, obj interface{}) *ReplaceQuery
 	}
 }
 
-// Execute runs the ReplaceQuery. No actions are taken until this query is run.
+// WithContext will consume the supplied context. A note that this is completely optional
+// as Timeout will auto-create a context for you if one is not supplied.
+// This is typically useful when using a context across many functions (e.g. to propagate a trace).
+func (rq *ReplaceQuery) WithContext(ctx context.Context) *ReplaceQuery {
+	rq.Query.setContext(&ctx)
+	return rq
+}
+
+// Retry overrides the connection's RetryPolicy for this query. Use RetryPolicy{MaxAttempts: 1} to disable retrying.
+func (rq *ReplaceQuery) Retry(policy RetryPolicy) *ReplaceQuery {
+	rq.Query.setRetry(policy)
+	return rq
+}
+
+// ReadPreference overrides the read preference for this query only.
+// e.g. ReadPreference(readpref.SecondaryPreferred())
+func (rq *ReplaceQuery) ReadPreference(rp *readpref.ReadPref) *ReplaceQuery {
+	rq.Query.setReadPreference(rp)
+	return rq
+}
+
+// ReadConcern overrides the read concern for this query only.
+func (rq *ReplaceQuery) ReadConcern(rc *readconcern.ReadConcern) *ReplaceQuery {
+	rq.Query.setReadConcern(rc)
+	return rq
+}
+
+// WriteConcern overrides the write concern for this query only.
+func (rq *ReplaceQuery) WriteConcern(wc *writeconcern.WriteConcern) *ReplaceQuery {
+	rq.Query.setWriteConcern(wc)
+	return rq
+}
+
+// Flags overrides the read concern, read preference and write concern set in flags for this query only.
+// e.g. Flags(ReadPreferenceSecondaryPreferred | ReadConcernMajority)
+func (rq *ReplaceQuery) Flags(flags ConnectionFlag) *ReplaceQuery {
+	rq.Query.setFlags(flags)
+	return rq
+}
+
+// ErrorIfNotFound returns ErrNoDocuments should the filter match no documents, regardless of StrictNotFound.
+func (rq *ReplaceQuery) ErrorIfNotFound() *ReplaceQuery {
+	rq.Query.setErrorIfNotFound(true)
+	return rq
+}
+
+// IgnoreNotFound does not return an error should the filter match no documents, regardless of StrictNotFound.
+func (rq *ReplaceQuery) IgnoreNotFound() *ReplaceQuery {
+	rq.Query.setErrorIfNotFound(false)
+	return rq
+}
+
+// One runs the ReplaceQuery against the first matching document.
+// ErrNoDocuments is returned if nothing matched when StrictNotFound or ErrorIfNotFound is set.
+// No actions are taken until this query is run.
 func (rq *ReplaceQuery) One() error {
 	// var result *mongo.UpdateResult
 	opts := options.Replace()
 	// TODO: ReplaceOptions
-	ctx, cancelFunc := rq.getContext()
-	defer cancelFunc()
-
-	res, err := rq.collection.mongoColl.ReplaceOne(ctx, rq.filter, rq.newObj, opts)
-	if err != nil {
-		return err
-	}
-	err = rq.collection.handleErr(err)
-	// TODO: ReplaceQuery ErrNotFound behavior
-	_ = res
-	return nil
+	var res *mongo.UpdateResult
+	err := rq.run(opReplaceOne, func(ctx context.Context) (err error) {
+		res, err = rq.collection.mongoCollection().ReplaceOne(ctx, rq.filter, rq.newObj, opts)
+		if err != nil {
+			return err
+		}
+		return rq.notFoundErr(res.MatchedCount + res.UpsertedCount)
+	})
+	return rq.collection.handleErr(err)
 }
@@ -0,0 +1,248 @@
+package easymongo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// RetryPolicy controls how operations which fail with a transient error are retried. Retries happen on top
+// of the driver's own single retry. Only reads and writes which are safe to repeat are retried, operations
+// are never retried inside a transaction, and every attempt shares the budget of the query's Timeout/context.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made (including the first). Values of 1 or less disable retrying.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. The delay doubles with each later retry.
+	Backoff time.Duration
+	// Jitter randomizes each delay by up to this fraction (0-1) of the delay in either direction.
+	Jitter float64
+	// RetryOn decides whether an error should be retried. When it is nil, reads are retried on any
+	// transient error (see IsRetryableError). Writes are only ever retried if the error shows the write
+	// was not applied (e.g. no primary was available during an election), as a write which failed with an
+	// ambiguous error (e.g. a network error) may already have been applied. RetryOn can therefore only widen
+	// what is retried for reads - for writes, it can only narrow it.
+	RetryOn func(err error) bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts, starting with a 100ms backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     100 * time.Millisecond,
+	Jitter:      0.2,
+}
+
+// RetryPolicy sets the policy used to retry operations which fail with a transient error.
+// It can be overridden per query using Retry().
+// e.g. ConnectWith(mongoURI).RetryPolicy(DefaultRetryPolicy).Connect()
+func (cb *ConnectionBuilder) RetryPolicy(policy RetryPolicy) *ConnectionBuilder {
+	cb.connection.mongoOptions.retryPolicy = &policy
+	return cb
+}
+
+// setRetry overrides the connection's RetryPolicy for the query
+func (q *Query) setRetry(policy RetryPolicy) *Query {
+	q.retryPolicy = &policy
+	return q
+}
+
+// retryPolicy returns the policy which applies to the query (or nil if retrying is disabled)
+func (c *Collection) retryPolicy(q *Query) *RetryPolicy {
+	policy := c.Connection().mongoOptions.retryPolicy
+	if q != nil && q.retryPolicy != nil {
+		policy = q.retryPolicy
+	}
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return nil
+	}
+	return policy
+}
+
+// Server error codes which are safe to retry
+const (
+	codeHostUnreachable                 = 6
+	codeHostNotFound                    = 7
+	codeNetworkTimeout                  = 89
+	codeShutdownInProgress              = 91
+	codeWriteConflict                   = 112
+	codePrimarySteppedDown              = 189
+	codeExceededTimeLimit               = 262
+	codeSocketException                 = 9001
+	codeNotWritablePrimary              = 10107
+	codeInterruptedAtShutdown           = 11600
+	codeInterruptedDueToReplStateChange = 11602
+	codeNotPrimaryNoSecondaryOk         = 13435
+	codeNotPrimaryOrSecondary           = 13436
+)
+
+// notAppliedCodes are returned when the server rejected a write before applying it
+var notAppliedCodes = []int{
+	codeShutdownInProgress,
+	codeWriteConflict,
+	codePrimarySteppedDown,
+	codeNotWritablePrimary,
+	codeInterruptedAtShutdown,
+	codeInterruptedDueToReplStateChange,
+	codeNotPrimaryNoSecondaryOk,
+	codeNotPrimaryOrSecondary,
+}
+
+// transientCodes are returned for failures which may succeed if tried again
+var transientCodes = append([]int{
+	codeHostUnreachable,
+	codeHostNotFound,
+	codeNetworkTimeout,
+	codeExceededTimeLimit,
+	codeSocketException,
+}, notAppliedCodes...)
+
+// IsRetryableError returns true if err is transient, meaning the operation may succeed if it is run again.
+// This includes network errors, server selection failures (e.g. during an election), errors labelled
+// RetryableWriteError or TransientTransactionError, and codes such as NotWritablePrimary and WriteConflict.
+// Timeouts of the caller's own context are not retryable.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) ||
+		errors.Is(err, ErrTimeoutOccurred) {
+		return false
+	}
+	if isWriteNotApplied(err) || mongo.IsNetworkError(err) {
+		return true
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		if serverErr.HasErrorLabel("RetryableWriteError") || serverErr.HasErrorLabel("TransientTransactionError") {
+			return true
+		}
+		for _, code := range transientCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isWriteNotApplied returns true if err shows a write was never applied, so it can safely be run again
+func isWriteNotApplied(err error) bool {
+	var selectionErr topology.ServerSelectionError
+	if errors.As(err, &selectionErr) {
+		// The caller's context expiring is not transient
+		return !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled)
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		for _, code := range notAppliedCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shouldRetry returns true if the operation should be run again after failing with err
+func (policy *RetryPolicy) shouldRetry(op operation, err error) bool {
+	if op.write && !isWriteNotApplied(err) {
+		// The write may have been applied, so running it again could apply it twice
+		return false
+	}
+	if policy.RetryOn != nil {
+		return policy.RetryOn(err)
+	}
+	return op.write || IsRetryableError(err)
+}
+
+// delay returns how long to wait before the provided retry (starting at 1)
+func (policy *RetryPolicy) delay(retry int) time.Duration {
+	d := policy.Backoff << uint(retry-1)
+	if d < 0 {
+		// Overflowed
+		d = policy.Backoff
+	}
+	if policy.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * policy.Jitter * float64(d))
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// retryable returns true if the operation may be retried. Operations which are not idempotent
+// (e.g. UpdateQuery.All), aggregations which write ($out/$merge) and operations in a transaction are not.
+func (c *Collection) retryable(op operation, q *Query) bool {
+	if !op.retryable || c.database.tx != nil {
+		return false
+	}
+	if op.command == "aggregate" && q != nil && pipelineWrites(q.filter) {
+		return false
+	}
+	return true
+}
+
+// pipelineWrites returns true if the pipeline has an $out or $merge stage
+func pipelineWrites(pipeline interface{}) bool {
+	raw, err := bson.Marshal(bson.D{{Key: "pipeline", Value: pipeline}})
+	if err != nil {
+		// If we can't tell, assume the worst
+		return true
+	}
+	stages, ok := bson.Raw(raw).Lookup("pipeline").ArrayOK()
+	if !ok {
+		return false
+	}
+	values, err := stages.Values()
+	if err != nil {
+		return true
+	}
+	for _, stage := range values {
+		doc, ok := stage.DocumentOK()
+		if !ok {
+			continue
+		}
+		if _, err := doc.LookupErr("$out"); err == nil {
+			return true
+		}
+		if _, err := doc.LookupErr("$merge"); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// attempt runs fn, retrying it according to policy (which may be nil). The number of attempts made is returned.
+// A retry is abandoned (returning the last error) if ctx would expire during the backoff.
+func (c *Collection) attempt(ctx context.Context, op operation, q *Query, fn func(ctx context.Context) error) (attempts int, err error) {
+	var policy *RetryPolicy
+	if c.retryable(op, q) {
+		policy = c.retryPolicy(q)
+	}
+	for attempts = 1; ; attempts++ {
+		err = fn(withOperationStart(ctx))
+		if err == nil || policy == nil || attempts >= policy.MaxAttempts || !policy.shouldRetry(op, err) {
+			return attempts, err
+		}
+		delay := policy.delay(attempts)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return attempts, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempts, err
+		case <-timer.C:
+		}
+		c.Connection().logger().WithFields(Fields{
+			"db":         c.database.dbName,
+			"collection": c.collectionName,
+			"operation":  op.name,
+			"attempt":    attempts + 1,
+			"error":      err,
+		}).Debugf("Retrying %s after a transient error: %v", op.name, err)
+	}
+}
@@ -0,0 +1,91 @@
+package easymongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	is := assert.New(t)
+	is.True(easymongo.IsRetryableError(mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}))
+	is.True(easymongo.IsRetryableError(mongo.CommandError{Code: 112, Name: "WriteConflict"}))
+	is.True(easymongo.IsRetryableError(mongo.CommandError{Code: 1, Labels: []string{"RetryableWriteError"}}))
+	is.True(easymongo.IsRetryableError(mongo.CommandError{Code: 1, Labels: []string{"NetworkError"}}))
+	is.False(easymongo.IsRetryableError(mongo.CommandError{Code: 11000, Name: "DuplicateKey"}))
+	is.False(easymongo.IsRetryableError(context.DeadlineExceeded), "The caller's own timeout should not be retried")
+	is.False(easymongo.IsRetryableError(mongo.ErrNoDocuments))
+	is.False(easymongo.IsRetryableError(nil))
+}
+
+func TestRetryPolicyWrites(t *testing.T) {
+	is := assert.New(t)
+	alwaysRetry := easymongo.RetryPolicy{MaxAttempts: 3, RetryOn: func(err error) bool { return true }}
+	networkErr := mongo.CommandError{Code: 9001, Labels: []string{"NetworkError"}}
+	is.False(alwaysRetry.ShouldRetry(true, networkErr),
+		"A write which may have been applied should never be retried, even when RetryOn allows it")
+	is.True(alwaysRetry.ShouldRetry(false, networkErr), "RetryOn should decide for reads")
+	notApplied := mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}
+	is.True(alwaysRetry.ShouldRetry(true, notApplied), "A write which was not applied may be retried")
+
+	neverRetry := easymongo.RetryPolicy{MaxAttempts: 3, RetryOn: func(err error) bool { return false }}
+	is.False(neverRetry.ShouldRetry(true, notApplied), "RetryOn should still be able to narrow what writes retry")
+
+	defaultPolicy := easymongo.DefaultRetryPolicy
+	is.False(defaultPolicy.ShouldRetry(true, networkErr))
+	is.True(defaultPolicy.ShouldRetry(true, notApplied))
+	is.True(defaultPolicy.ShouldRetry(false, networkErr))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	is := assert.New(t)
+	tracer := &fakeTracer{}
+	var retried []error
+	// Nothing is listening on this port, so server selection fails quickly and is retried
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		ConnectTimeout(20 * time.Millisecond).
+		DefaultQueryTimeout(5 * time.Second).
+		Tracer(tracer).
+		RetryPolicy(easymongo.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			RetryOn: func(err error) bool {
+				retried = append(retried, err)
+				return easymongo.IsRetryableError(err)
+			},
+		}).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+	coll := tmpConn.Database("batman_archive").C("enemies")
+
+	var e enemy
+	is.Error(coll.Find(bson.M{}).One(&e))
+	is.Len(retried, 2, "Both failures before the final attempt should be offered for retry")
+	is.Equal(3, tracer.spans[0].attributes[easymongo.AttributeAttempts])
+
+	retried = nil
+	is.Error(coll.Find(bson.M{}).Retry(easymongo.RetryPolicy{MaxAttempts: 1}).One(&e))
+	is.Empty(retried, "A per-query policy should override the connection policy")
+	is.NotContains(tracer.spans[1].attributes, easymongo.AttributeAttempts)
+
+	retried = nil
+	_, _, err = coll.Update(bson.M{}, bson.M{"$inc": bson.M{"timesFought": 1}}).All()
+	is.Error(err)
+	is.Empty(retried, "Multi-document writes should never be retried")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	err = coll.Find(bson.M{}).WithContext(ctx).Retry(easymongo.RetryPolicy{MaxAttempts: 10, Backoff: time.Second}).One(&e)
+	is.Error(err)
+	is.False(errors.Is(err, mongo.ErrNoDocuments))
+	is.NotContains(tracer.spans[3].attributes, easymongo.AttributeAttempts,
+		"A retry should be abandoned if the backoff exceeds the context budget")
+}
@@ -0,0 +1,243 @@
+package easymongo
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// SlowQueryThreshold reports any command which takes longer than threshold to the connection's Logger
+// at the warn level. The report includes the database, collection, command name, filter shape (the filter with
+// every value replaced by "?"), duration, comment and the number of documents returned or modified.
+// Commands are only decoded once they have exceeded the threshold, so it is cheap enough to leave on in production.
+// e.g. ConnectWith(mongoURI).SlowQueryThreshold(200 * time.Millisecond).Connect()
+func (cb *ConnectionBuilder) SlowQueryThreshold(threshold time.Duration) *ConnectionBuilder {
+	cb.connection.mongoOptions.slowQueryThreshold = &threshold
+	return cb
+}
+
+// SlowQueryThresholdFor overrides the slow query threshold of a single collection. namespace is either a
+// collection name (which applies in every database) or a "database.collection" pair.
+// e.g. ConnectWith(mongoURI).SlowQueryThreshold(time.Second).SlowQueryThresholdFor("batman_archive.enemies", 50 * time.Millisecond)
+func (cb *ConnectionBuilder) SlowQueryThresholdFor(namespace string, threshold time.Duration) *ConnectionBuilder {
+	if cb.connection.mongoOptions.slowQueryOverrides == nil {
+		cb.connection.mongoOptions.slowQueryOverrides = map[string]time.Duration{}
+	}
+	cb.connection.mongoOptions.slowQueryOverrides[namespace] = threshold
+	return cb
+}
+
+// slowQueryMonitor reports commands which exceed the configured thresholds. It is called by the connection's
+// command monitor with the raw events, so only the commands which turn out to be slow are decoded.
+type slowQueryMonitor struct {
+	conn      *Connection
+	threshold *time.Duration
+	overrides map[string]time.Duration
+	// inFlight maps an inFlightKey to the *slowQueryStarted of a running command which has a threshold
+	inFlight sync.Map
+}
+
+// slowQueryStarted holds what is needed to report a running command, should it turn out to be slow
+type slowQueryStarted struct {
+	database   string
+	collection string
+	command    bson.Raw
+	threshold  time.Duration
+}
+
+// enableSlowQueryMonitor sets up the slow query monitor (should a threshold have been configured)
+func (conn *Connection) enableSlowQueryMonitor() {
+	if conn.mongoOptions.slowQueryThreshold == nil && len(conn.mongoOptions.slowQueryOverrides) == 0 {
+		return
+	}
+	conn.slowQueries = &slowQueryMonitor{
+		conn:      conn,
+		threshold: conn.mongoOptions.slowQueryThreshold,
+		overrides: conn.mongoOptions.slowQueryOverrides,
+	}
+}
+
+// thresholdFor returns the threshold which applies to the collection. found is false if no threshold applies.
+func (sq *slowQueryMonitor) thresholdFor(database, collection string) (threshold time.Duration, found bool) {
+	if threshold, found = sq.overrides[database+"."+collection]; found {
+		return threshold, true
+	}
+	if threshold, found = sq.overrides[collection]; found {
+		return threshold, true
+	}
+	if sq.threshold != nil {
+		return *sq.threshold, true
+	}
+	return 0, false
+}
+
+// started keeps the raw command of e until it finishes, should a threshold apply to it
+func (sq *slowQueryMonitor) started(e *event.CommandStartedEvent) {
+	collection := rawCommandCollection(e.CommandName, e.Command)
+	threshold, found := sq.thresholdFor(e.DatabaseName, collection)
+	if !found {
+		return
+	}
+	sq.inFlight.Store(inFlightKey{connectionID: e.ConnectionID, requestID: e.RequestID}, &slowQueryStarted{
+		database:   e.DatabaseName,
+		collection: collection,
+		command:    e.Command,
+		threshold:  threshold,
+	})
+}
+
+// finished returns the started command of e (if a threshold applies to it), should it have taken longer than the threshold
+func (sq *slowQueryMonitor) finished(e event.CommandFinishedEvent) (started *slowQueryStarted, slow bool) {
+	value, found := sq.inFlight.LoadAndDelete(inFlightKey{connectionID: e.ConnectionID, requestID: e.RequestID})
+	if !found {
+		return nil, false
+	}
+	started = value.(*slowQueryStarted)
+	return started, time.Duration(e.DurationNanos) > started.threshold
+}
+
+func (sq *slowQueryMonitor) succeeded(e *event.CommandSucceededEvent) {
+	started, slow := sq.finished(e.CommandFinishedEvent)
+	if !slow {
+		return
+	}
+	duration := time.Duration(e.DurationNanos)
+	sq.conn.logger().WithFields(started.fields(e.CommandName, duration)).
+		WithField("documents", replyDocumentCount(e.CommandName, e.Reply)).
+		Warnf("Slow query: %s took %s (threshold %s)", e.CommandName, duration, started.threshold)
+}
+
+func (sq *slowQueryMonitor) failed(e *event.CommandFailedEvent) {
+	started, slow := sq.finished(e.CommandFinishedEvent)
+	if !slow {
+		return
+	}
+	duration := time.Duration(e.DurationNanos)
+	sq.conn.logger().WithFields(started.fields(e.CommandName, duration)).
+		WithField("error", e.Failure).
+		Warnf("Slow query: %s failed after %s (threshold %s)", e.CommandName, duration, started.threshold)
+}
+
+// fields returns the logging fields describing a slow command. The filter is logged as its shape
+// (see OpError.FilterShape), so none of its values are logged.
+func (started *slowQueryStarted) fields(commandName string, duration time.Duration) Fields {
+	fields := Fields{
+		"db":         started.database,
+		"collection": started.collection,
+		"command":    commandName,
+		"duration":   duration,
+		"threshold":  started.threshold,
+	}
+	var cmd bson.D
+	if err := bson.Unmarshal(started.command, &cmd); err != nil {
+		return fields
+	}
+	if filter, found := commandFilter(commandName, cmd); found {
+		if shape, ok := filterShape(filter); ok {
+			fields["filter"] = shape
+		}
+	}
+	if comment, found := lookup(cmd, "comment"); found {
+		fields["comment"] = comment
+	}
+	return fields
+}
+
+// rawCommandCollection returns the collection a command runs against without decoding the command
+func rawCommandCollection(commandName string, cmd bson.Raw) string {
+	if len(cmd) == 0 {
+		return ""
+	}
+	if first, err := cmd.IndexErr(0); err == nil && first.Key() == commandName {
+		if coll, ok := first.Value().StringValueOK(); ok {
+			return coll
+		}
+	}
+	if commandName == "getMore" {
+		if coll, ok := cmd.Lookup("collection").StringValueOK(); ok {
+			return coll
+		}
+	}
+	return ""
+}
+
+// commandFilter returns the filter (or pipeline) of a command. The filter of the first statement
+// is returned for update and delete commands.
+func commandFilter(commandName string, cmd bson.D) (interface{}, bool) {
+	switch commandName {
+	case "find":
+		return lookup(cmd, "filter")
+	case "aggregate":
+		return lookup(cmd, "pipeline")
+	case "count", "distinct", "findAndModify":
+		return lookup(cmd, "query")
+	case "update", "delete":
+		statements, _ := lookup(cmd, commandName+"s")
+		if statements, ok := statements.(bson.A); ok && len(statements) > 0 {
+			if statement, ok := statements[0].(bson.D); ok {
+				return lookup(statement, "q")
+			}
+		}
+	}
+	return nil, false
+}
+
+// replyDocumentCount returns the number of documents a command returned or modified.
+// Only the fields holding the count are read, so the reply (e.g. a large batch) is not decoded.
+func replyDocumentCount(commandName string, reply bson.Raw) int64 {
+	switch commandName {
+	case "find", "aggregate", "getMore":
+		for _, batch := range []string{"firstBatch", "nextBatch"} {
+			if docs, ok := reply.Lookup("cursor", batch).ArrayOK(); ok {
+				values, _ := docs.Values()
+				return int64(len(values))
+			}
+		}
+	case "distinct":
+		if values, ok := reply.Lookup("values").ArrayOK(); ok {
+			elems, _ := values.Values()
+			return int64(len(elems))
+		}
+	case "update":
+		if modified, err := reply.LookupErr("nModified"); err == nil {
+			return rawInt64(modified)
+		}
+	case "findAndModify":
+		return rawInt64(reply.Lookup("lastErrorObject", "n"))
+	}
+	return rawInt64(reply.Lookup("n"))
+}
+
+// lookup returns the value of key within doc
+func lookup(doc bson.D, key string) (interface{}, bool) {
+	for _, elem := range doc {
+		if elem.Key == key {
+			return elem.Value, true
+		}
+	}
+	return nil, false
+}
+
+// rawInt64 converts the numeric types a reply may hold to an int64
+func rawInt64(v bson.RawValue) int64 {
+	if n, ok := v.AsInt64OK(); ok {
+		return n
+	}
+	return 0
+}
+
+// extJSONValue renders a document or array as relaxed extended JSON for logging
+func extJSONValue(v interface{}) string {
+	if doc, ok := v.(bson.D); ok {
+		return extJSON(doc)
+	}
+	// Arrays (e.g. a pipeline) can't be marshaled on their own, so marshal them in a wrapper and unwrap them
+	out, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: v}}, false, false)
+	if err != nil {
+		return "<could not render as JSON: " + err.Error() + ">"
+	}
+	// Strip the {"v": and trailing }
+	return string(out[len(`{"v":`) : len(out)-1])
+}
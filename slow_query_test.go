@@ -0,0 +1,107 @@
+package easymongo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestSlowQueryThreshold(t *testing.T) {
+	setup(t)
+	is := assert.New(t)
+	createBatmanArchive(t)
+	log := &twoMethodLogger{}
+	// A threshold of 0 reports every command, while the override keeps the other collection quiet
+	tmpConn, err := easymongo.ConnectWith(conn.MongoURI()).
+		SkipGlobal().
+		Logger(log).
+		SlowQueryThreshold(0).
+		SlowQueryThresholdFor("batman_archive.allies", time.Hour).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+
+	var enemies []enemy
+	err = tmpConn.Database("batman_archive").C("enemies").Find(bson.M{"name": "The Joker"}).Comment("whodunnit").All(&enemies)
+	is.NoError(err)
+	var allies []enemy
+	is.NoError(tmpConn.Database("batman_archive").C("allies").Find(bson.M{}).All(&allies))
+
+	var slow []string
+	for _, line := range log.lines {
+		if strings.Contains(line, "Slow query") {
+			slow = append(slow, line)
+		}
+	}
+	is.Len(slow, 1, "Only the query against enemies should be reported")
+	if len(slow) == 1 {
+		is.Contains(slow[0], "collection=enemies")
+		is.Contains(slow[0], "command=find")
+		is.Contains(slow[0], "comment=whodunnit")
+		is.Contains(slow[0], "documents=1")
+		is.Contains(slow[0], `filter={"name":"?"}`, "The shape of the filter should be logged")
+		is.NotContains(slow[0], "The Joker")
+	}
+}
+
+func TestSlowQueryMonitor(t *testing.T) {
+	is := assert.New(t)
+	log := &twoMethodLogger{}
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		Logger(log).
+		SlowQueryThreshold(time.Second).
+		SlowQueryThresholdFor("enemies", 10*time.Millisecond).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+	monitor := tmpConn.CommandMonitor()
+
+	find := func(requestID int64, collection string) {
+		cmd, err := bson.Marshal(bson.D{
+			{Key: "find", Value: collection},
+			{Key: "filter", Value: bson.D{{Key: "email", Value: "bruce@wayne.com"}, {Key: "age", Value: bson.D{{Key: "$gt", Value: 30}}}}},
+			{Key: "comment", Value: "whodunnit"},
+			{Key: "$db", Value: "batman_archive"},
+		})
+		is.NoError(err)
+		monitor.Started(context.Background(), &event.CommandStartedEvent{
+			Command: cmd, DatabaseName: "batman_archive", CommandName: "find", RequestID: requestID, ConnectionID: "c1",
+		})
+	}
+	reply, err := bson.Marshal(bson.D{
+		{Key: "cursor", Value: bson.D{{Key: "firstBatch", Value: bson.A{bson.D{}, bson.D{}}}, {Key: "id", Value: int64(0)}}},
+		{Key: "ok", Value: 1.0},
+	})
+	is.NoError(err)
+	succeeded := func(requestID int64, duration time.Duration) {
+		monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{
+				DurationNanos: duration.Nanoseconds(), CommandName: "find", RequestID: requestID, ConnectionID: "c1",
+			},
+			Reply: reply,
+		})
+	}
+
+	find(1, "enemies")
+	succeeded(1, 5*time.Millisecond)
+	find(2, "allies")
+	succeeded(2, 500*time.Millisecond)
+	is.Empty(log.lines, "Commands within their threshold should not be reported")
+
+	find(3, "enemies")
+	succeeded(3, 50*time.Millisecond)
+	is.Len(log.lines, 1)
+	line := strings.Join(log.lines, "\n")
+	for _, field := range []string{"Slow query: find took 50ms (threshold 10ms)", "db=batman_archive", "collection=enemies",
+		`filter={"email":"?","age":{"$gt":"?"}}`, "comment=whodunnit", "documents=2"} {
+		is.Contains(line, field)
+	}
+	is.NotContains(line, "bruce@wayne.com", "Filter values should not be logged")
+}
@@ -0,0 +1,150 @@
+package easymongo
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TimePolicy controls how time.Time values are encoded and decoded. BSON dates only hold milliseconds
+// and no time zone, so a time rarely equals itself after a round trip. Use Normalize to get the value
+// a time will be read back as.
+type TimePolicy struct {
+	// StoreUTC stores and reads back times in UTC. When false (and DecodeLocation is nil), times are read back in time.Local.
+	StoreUTC bool
+	// TruncateTo truncates times before they are stored (e.g. time.Millisecond or time.Second).
+	// BSON dates always drop anything below a millisecond.
+	TruncateTo time.Duration
+	// DecodeLocation is the location times are read back in, overriding StoreUTC
+	DecodeLocation *time.Location
+	// ZeroIsNull stores a zero time.Time as null rather than as 0001-01-01, so a zero time.Time and a nil
+	// *time.Time are stored the same way. null is always read back as a zero time.Time or a nil *time.Time.
+	ZeroIsNull bool
+}
+
+// UTCMillisTimePolicy stores and reads back times in UTC, truncated to the millisecond, and stores zero times as null.
+// It is not applied unless passed to ConnectionBuilder.TimePolicy() - without a policy, the driver's defaults apply
+// (times are read back in time.Local and zero times are stored as 0001-01-01).
+var UTCMillisTimePolicy = TimePolicy{
+	StoreUTC:   true,
+	TruncateTo: time.Millisecond,
+	ZeroIsNull: true,
+}
+
+// TimePolicy sets how time.Time values are encoded and decoded.
+// e.g. ConnectWith(mongoURI).TimePolicy(TimePolicy{StoreUTC: true, TruncateTo: time.Millisecond}).Connect()
+func (cb *ConnectionBuilder) TimePolicy(policy TimePolicy) *ConnectionBuilder {
+	return cb.Codecs(policy.Codec())
+}
+
+// Codec returns the codec which applies the policy, for use with Codecs()
+func (tp TimePolicy) Codec() TypeCodec {
+	return TypeCodec{Type: tTime, Codec: timeCodec{policy: tp}}
+}
+
+// Normalize returns t as it will be read back once stored using the policy.
+// e.g. is.Equal(policy.Normalize(expected.CreatedAt), found.CreatedAt)
+func (tp TimePolicy) Normalize(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	if tp.TruncateTo > time.Millisecond {
+		t = t.Truncate(tp.TruncateTo)
+	} else {
+		t = t.Truncate(time.Millisecond)
+	}
+	return t.In(tp.location())
+}
+
+// location returns the location times are decoded in
+func (tp TimePolicy) location() *time.Location {
+	if tp.DecodeLocation != nil {
+		return tp.DecodeLocation
+	}
+	if tp.StoreUTC {
+		return time.UTC
+	}
+	return time.Local
+}
+
+var tTime = reflect.TypeOf(time.Time{})
+
+// timeCodec encodes and decodes time.Time values according to a TimePolicy
+type timeCodec struct {
+	policy TimePolicy
+}
+
+func (tc timeCodec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tTime {
+		return bsoncodec.ValueEncoderError{Name: "TimeEncodeValue", Types: []reflect.Type{tTime}, Received: val}
+	}
+	t := val.Interface().(time.Time)
+	if t.IsZero() && tc.policy.ZeroIsNull {
+		return vw.WriteNull()
+	}
+	if tc.policy.TruncateTo > 0 {
+		t = t.Truncate(tc.policy.TruncateTo)
+	}
+	if tc.policy.StoreUTC {
+		t = t.UTC()
+	}
+	return vw.WriteDateTime(int64(primitive.NewDateTimeFromTime(t)))
+}
+
+func (tc timeCodec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tTime {
+		return bsoncodec.ValueDecoderError{Name: "TimeDecodeValue", Types: []reflect.Type{tTime}, Received: val}
+	}
+	var t time.Time
+	switch vr.Type() {
+	case bsontype.DateTime:
+		dt, err := vr.ReadDateTime()
+		if err != nil {
+			return err
+		}
+		t = primitive.DateTime(dt).Time()
+	case bsontype.Int64:
+		ms, err := vr.ReadInt64()
+		if err != nil {
+			return err
+		}
+		t = primitive.DateTime(ms).Time()
+	case bsontype.Timestamp:
+		seconds, _, err := vr.ReadTimestamp()
+		if err != nil {
+			return err
+		}
+		t = time.Unix(int64(seconds), 0)
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into a time.Time", vr.Type())
+	}
+	if t.IsZero() {
+		// Keep zero times comparable to time.Time{}
+		t = time.Time{}
+	} else {
+		t = t.In(tc.policy.location())
+	}
+	val.Set(reflect.ValueOf(t))
+	return nil
+}
@@ -0,0 +1,53 @@
+package easymongo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+type appointment struct {
+	At       time.Time  `bson:"at"`
+	Canceled time.Time  `bson:"canceled"`
+	Moved    *time.Time `bson:"moved"`
+}
+
+func TestTimePolicy(t *testing.T) {
+	is := assert.New(t)
+	policy := easymongo.TimePolicy{StoreUTC: true, TruncateTo: time.Second, ZeroIsNull: true}
+	codec := policy.Codec()
+	registry := bson.NewRegistryBuilder().
+		RegisterTypeEncoder(codec.Type, codec.Codec).
+		RegisterTypeDecoder(codec.Type, codec.Codec).
+		Build()
+
+	gotham, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		gotham = time.FixedZone("EST", -5*60*60)
+	}
+	original := appointment{At: time.Date(2021, 10, 31, 23, 59, 59, 987654321, gotham)}
+	raw, err := bson.MarshalWithRegistry(registry, original)
+	is.NoError(err)
+	is.Equal(bsontype.Null, bson.Raw(raw).Lookup("canceled").Type, "A zero time should be stored as null")
+	is.Equal(bsontype.Null, bson.Raw(raw).Lookup("moved").Type)
+
+	var decoded appointment
+	is.NoError(bson.UnmarshalWithRegistry(registry, raw, &decoded))
+	is.Equal(policy.Normalize(original.At), decoded.At, "The decoded time should equal the normalized time")
+	is.Equal(time.UTC, decoded.At.Location())
+	is.Equal(0, decoded.At.Nanosecond(), "The time should be truncated to the second")
+	is.Equal(time.Time{}, decoded.Canceled, "null should decode to a zero time.Time")
+	is.Nil(decoded.Moved, "null should decode to a nil *time.Time")
+
+	policy.DecodeLocation = gotham
+	codec = policy.Codec()
+	registry = bson.NewRegistryBuilder().RegisterTypeDecoder(codec.Type, codec.Codec).Build()
+	is.NoError(bson.UnmarshalWithRegistry(registry, raw, &decoded))
+	is.Equal(gotham, decoded.At.Location())
+	is.True(policy.Normalize(original.At).Equal(decoded.At))
+	is.Equal(time.Time{}, policy.Normalize(time.Time{}))
+}
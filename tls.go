@@ -0,0 +1,231 @@
+package easymongo
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/youmark/pkcs8"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// x509AuthMechanism is the mechanism mongo uses when authenticating with a client certificate
+const x509AuthMechanism = "MONGODB-X509"
+
+// TLSConfig holds the file based TLS settings used when connecting to a cluster which requires TLS
+// (and optionally mutual TLS).
+type TLSConfig struct {
+	// CAFile is the path to a PEM encoded file containing the certificate authorities used to validate the server.
+	// If unset, the system certificate pool is used.
+	CAFile string
+	// CertKeyFile is the path to a PEM encoded file containing both the client certificate and private key.
+	// This is required for mutual TLS and x509 authentication.
+	CertKeyFile string
+	// KeyPassword is used to decrypt the private key in CertKeyFile (should it be encrypted).
+	KeyPassword string
+	// InsecureSkipVerify disables validation of the server certificate and host name.
+	// This should never be done in a production environment.
+	InsecureSkipVerify bool
+}
+
+// TLS enables TLS on the connection using the provided file based settings.
+// e.g. ConnectWith(mongoURI).TLS(TLSConfig{CAFile: "/etc/ssl/mongo/ca.pem", CertKeyFile: "/etc/ssl/mongo/client.pem"}).Connect()
+// If TLSConfig() has also been called, these settings are layered on top of the provided *tls.Config.
+func (cb *ConnectionBuilder) TLS(cfg TLSConfig) *ConnectionBuilder {
+	cb.connection.mongoOptions.tlsOptions = &cfg
+	return cb
+}
+
+// TLSConfig enables TLS on the connection using an already constructed *tls.Config.
+// This is useful if certificates are loaded from somewhere other than the filesystem.
+func (cb *ConnectionBuilder) TLSConfig(cfg *tls.Config) *ConnectionBuilder {
+	cb.connection.mongoOptions.tlsConfig = cfg
+	return cb
+}
+
+// X509Auth authenticates using the MONGODB-X509 mechanism. The username is derived from the subject
+// of the client certificate supplied with TLS() or TLSConfig(), so a client certificate is required.
+func (cb *ConnectionBuilder) X509Auth() *ConnectionBuilder {
+	cb.connection.mongoOptions.x509Auth = true
+	return cb
+}
+
+// tlsEnabled returns true if any TLS settings were provided
+func (mo *MongoConnectOptions) tlsEnabled() bool {
+	return mo.tlsOptions != nil || mo.tlsConfig != nil
+}
+
+// applyTLS sets the TLS configuration (and x509 credentials, if requested) on the client options.
+func (mo *MongoConnectOptions) applyTLS(opts *options.ClientOptions) error {
+	if !mo.tlsEnabled() {
+		if mo.x509Auth {
+			return fmt.Errorf("x509 authentication requires a client certificate to be configured using TLS() or TLSConfig()")
+		}
+		return nil
+	}
+	tlsConfig, subject, err := mo.buildTLSConfig()
+	if err != nil {
+		return err
+	}
+	opts.SetTLSConfig(tlsConfig)
+
+	if mo.x509Auth {
+		if subject == "" {
+			return fmt.Errorf("x509 authentication requires a client certificate, but none was configured")
+		}
+		cred := options.Credential{
+			AuthMechanism: x509AuthMechanism,
+			AuthSource:    "$external",
+			Username:      subject,
+		}
+		opts.SetAuth(cred)
+	}
+	return nil
+}
+
+// buildTLSConfig constructs the *tls.Config from the provided options. The subject of the client certificate
+// (if there is one) is returned so that it can be used as the x509 username.
+func (mo *MongoConnectOptions) buildTLSConfig() (cfg *tls.Config, subject string, err error) {
+	if mo.tlsConfig != nil {
+		cfg = mo.tlsConfig.Clone()
+	} else {
+		cfg = &tls.Config{}
+	}
+	if mo.tlsOptions != nil {
+		if mo.tlsOptions.InsecureSkipVerify {
+			cfg.InsecureSkipVerify = true
+		}
+		if mo.tlsOptions.CAFile != "" {
+			if err = addCACertFromFile(cfg, mo.tlsOptions.CAFile); err != nil {
+				return nil, "", fmt.Errorf("error configuring client, can't load CA file: %w", err)
+			}
+		}
+		if mo.tlsOptions.CertKeyFile != "" {
+			subject, err = addClientCertFromFile(cfg, mo.tlsOptions.CertKeyFile, mo.tlsOptions.KeyPassword)
+			if err != nil {
+				return nil, "", fmt.Errorf("error configuring client, can't load client certificate: %w", err)
+			}
+			return cfg, subject, nil
+		}
+	}
+	// The certificate came from the user provided *tls.Config
+	if len(cfg.Certificates) > 0 {
+		subject, err = certificateSubject(cfg.Certificates[0])
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return cfg, subject, nil
+}
+
+// addCACertFromFile adds a root CA certificate to the configuration given a path
+// to the containing file.
+func addCACertFromFile(cfg *tls.Config, file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if cfg.RootCAs == nil {
+		cfg.RootCAs = x509.NewCertPool()
+	}
+	if !cfg.RootCAs.AppendCertsFromPEM(data) {
+		return errors.New("the specified CA file does not contain any valid certificates")
+	}
+	return nil
+}
+
+// addClientCertFromFile loads a PEM file holding both the client certificate and private key
+// and adds it to the configuration. The subject of the certificate is returned.
+func addClientCertFromFile(cfg *tls.Config, file, keyPassword string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	var certBlock, keyBlock []byte
+	remaining := data
+	for {
+		var block *pem.Block
+		block, remaining = pem.Decode(remaining)
+		if block == nil {
+			break
+		}
+		switch {
+		case block.Type == "CERTIFICATE":
+			certBlock = append(certBlock, pem.EncodeToMemory(block)...)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			keyBlock, err = decodePrivateKey(block, keyPassword)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	if len(certBlock) == 0 {
+		return "", errors.New("failed to find CERTIFICATE")
+	}
+	if len(keyBlock) == 0 {
+		return "", errors.New("failed to find PRIVATE KEY")
+	}
+	cert, err := tls.X509KeyPair(certBlock, keyBlock)
+	if err != nil {
+		return "", err
+	}
+	cfg.Certificates = append(cfg.Certificates, cert)
+	return certificateSubject(cert)
+}
+
+// decodePrivateKey returns the PEM encoding of a private key block, decrypting it with keyPassword if necessary.
+func decodePrivateKey(block *pem.Block, keyPassword string) ([]byte, error) {
+	legacyEncrypted := x509.IsEncryptedPEMBlock(block)
+	pkcs8Encrypted := strings.Contains(block.Type, "ENCRYPTED PRIVATE KEY")
+	if !legacyEncrypted && !pkcs8Encrypted {
+		return pem.EncodeToMemory(block), nil
+	}
+	if keyPassword == "" {
+		return nil, errors.New("no password provided to decrypt private key")
+	}
+	var keyBytes []byte
+	if legacyEncrypted {
+		var err error
+		keyBytes, err = x509.DecryptPEMBlock(block, []byte(keyPassword))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(keyPassword))
+		if err != nil {
+			return nil, err
+		}
+		keyBytes, err = x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var encoded bytes.Buffer
+	if err := pem.Encode(&encoded, &pem.Block{Type: strings.TrimPrefix(block.Type, "ENCRYPTED "), Bytes: keyBytes}); err != nil {
+		return nil, err
+	}
+	return encoded.Bytes(), nil
+}
+
+// certificateSubject returns the RFC 2253 formatted subject of a client certificate. This is
+// the username mongo expects when authenticating with MONGODB-X509.
+func certificateSubject(cert tls.Certificate) (string, error) {
+	leaf := cert.Leaf
+	if leaf == nil {
+		if len(cert.Certificate) == 0 {
+			return "", errors.New("the client certificate is empty")
+		}
+		var err error
+		// tls.X509KeyPair does not retain the parsed leaf certificate
+		leaf, err = x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return "", err
+		}
+	}
+	return leaf.Subject.ToRDNSequence().String(), nil
+}
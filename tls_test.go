@@ -0,0 +1,78 @@
+package easymongo_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+)
+
+// writeClientCert generates a self-signed certificate and key and writes them to a single PEM file
+func writeClientCert(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate a private key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "batcomputer", OrganizationalUnit: []string{"Wayne Enterprises"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Could not create a certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Could not marshal the private key: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})...)
+	path := filepath.Join(t.TempDir(), "client.pem")
+	if err = ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Could not write the client certificate: %v", err)
+	}
+	return path
+}
+
+func TestTLS(t *testing.T) {
+	// Nothing is listening on this port - connecting only builds the client, which is enough to load the certificates
+	mongoURI := "mongodb://127.0.0.1:1"
+	t.Run("Missing CA file", func(t *testing.T) {
+		is := assert.New(t)
+		_, err := easymongo.ConnectWith(mongoURI).SkipGlobal().TLS(easymongo.TLSConfig{
+			CAFile: filepath.Join(t.TempDir(), "missing.pem"),
+		}).Connect()
+		is.Error(err, "A missing CA file should fail the connection")
+	})
+	t.Run("X509 without a certificate", func(t *testing.T) {
+		is := assert.New(t)
+		_, err := easymongo.ConnectWith(mongoURI).SkipGlobal().X509Auth().Connect()
+		is.Error(err, "x509 auth should require a client certificate")
+	})
+	t.Run("X509 with a certificate", func(t *testing.T) {
+		is := assert.New(t)
+		certFile := writeClientCert(t)
+		tmpConn, err := easymongo.ConnectWith(mongoURI).SkipGlobal().TLS(easymongo.TLSConfig{
+			CertKeyFile:        certFile,
+			InsecureSkipVerify: true,
+		}).X509Auth().Connect()
+		is.NoError(err, "The client certificate should be loaded")
+		if is.NotNil(tmpConn) {
+			defer tmpConn.Close(context.Background())
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package easymongo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Attribute keys set on every span. They follow the OpenTelemetry database semantic conventions.
+const (
+	// AttributeDBSystem is always "mongodb"
+	AttributeDBSystem = "db.system"
+	// AttributeDBName is the name of the database
+	AttributeDBName = "db.name"
+	// AttributeDBOperation is the mongo command run (e.g. "find")
+	AttributeDBOperation = "db.operation"
+	// AttributeDBCollection is the name of the collection
+	AttributeDBCollection = "db.mongodb.collection"
+	// AttributeDBStatement is the shape of the filter (or pipeline), with every value replaced by "?"
+	AttributeDBStatement = "db.statement"
+	// AttributeOperation is the easymongo method which was called (e.g. "FindQuery.All")
+	AttributeOperation = "easymongo.operation"
+	// AttributeAttempts is the number of attempts made, should the operation have been retried
+	AttributeAttempts = "easymongo.attempts"
+)
+
+// Attribute is a key/value pair set on a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts a span around every terminal operation (e.g. FindQuery.All, UpdateQuery.One).
+// An OpenTelemetry adapter is available in the github.com/tophergopher/easymongo/otel module.
+type Tracer interface {
+	// StartSpan starts a span which is a child of any span in ctx. The returned context holds the new span
+	// and is passed to the driver.
+	StartSpan(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	// SetAttributes adds attributes to the span
+	SetAttributes(attributes ...Attribute)
+	// RecordError marks the span as failed
+	RecordError(err error)
+	// End completes the span
+	End()
+}
+
+// Tracer sets the Tracer which wraps every terminal operation in a span. The span is parented from the
+// context supplied to the query (using WithContext()), or the transaction it is running in.
+// e.g. ConnectWith(mongoURI).Tracer(otel.NewTracer(otel.DefaultTracer())).Connect()
+func (cb *ConnectionBuilder) Tracer(tracer Tracer) *ConnectionBuilder {
+	cb.connection.mongoOptions.tracer = tracer
+	return cb
+}
+
+// startSpan starts the span of an operation (should a Tracer be set). span is nil if there is no Tracer.
+func (c *Collection) startSpan(ctx context.Context, op operation, filter interface{}) (context.Context, Span) {
+	tracer := c.Connection().mongoOptions.tracer
+	if tracer == nil {
+		return ctx, nil
+	}
+	dbName := c.database.dbName
+	attributes := []Attribute{
+		{Key: AttributeDBSystem, Value: "mongodb"},
+		{Key: AttributeDBName, Value: dbName},
+		{Key: AttributeDBOperation, Value: op.command},
+		{Key: AttributeDBCollection, Value: c.collectionName},
+		{Key: AttributeOperation, Value: op.name},
+	}
+	if shape, ok := filterShape(filter); ok {
+		attributes = append(attributes, Attribute{Key: AttributeDBStatement, Value: shape})
+	}
+	return tracer.StartSpan(ctx, op.command+" "+dbName+"."+c.collectionName, attributes...)
+}
+
+// endSpan records the result of an operation on span (should it be set) and ends it
+func endSpan(span Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// filterShape renders filter as relaxed extended JSON with every value replaced by "?", so it can be
+// recorded without leaking data. Maps are rendered with their keys sorted, so the same filter always
+// has the same shape. ok is false if there is no filter or it could not be marshaled.
+func filterShape(filter interface{}) (shape string, ok bool) {
+	if filter == nil {
+		return "", false
+	}
+	// Marshal the filter inside a wrapper so arrays (e.g. a pipeline) can be marshaled
+	if _, err := bson.Marshal(bson.D{{Key: "f", Value: filter}}); err != nil {
+		return "", false
+	}
+	return extJSONValue(shapeOf(filter)), true
+}
+
+// shapeOf replaces every value in v with "?". Documents keep their keys (maps are sorted by key, as their
+// order is random), arrays of documents keep each document's shape and arrays of values collapse to a single "?".
+func shapeOf(v interface{}) interface{} {
+	if d, isDoc := v.(primitive.D); isDoc {
+		out := make(primitive.D, len(d))
+		for i, elem := range d {
+			out[i] = primitive.E{Key: elem.Key, Value: shapeOf(elem.Value)}
+		}
+		return out
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return "?"
+		}
+		return shapeOf(rv.Elem().Interface())
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return "?"
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		out := make(primitive.D, len(keys))
+		for i, key := range keys {
+			out[i] = primitive.E{Key: key, Value: shapeOf(rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface())}
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Binary data (e.g. an ObjectID)
+			return "?"
+		}
+		out := primitive.A{}
+		for i := 0; i < rv.Len(); i++ {
+			if elem, isDoc := shapeOf(rv.Index(i).Interface()).(primitive.D); isDoc {
+				out = append(out, elem)
+			}
+		}
+		if len(out) == 0 {
+			out = append(out, "?")
+		}
+		return out
+	case reflect.Struct:
+		// Structs marshaled as documents keep the order of their fields
+		raw, err := bson.Marshal(v)
+		if err != nil {
+			return "?"
+		}
+		var doc primitive.D
+		if bson.Unmarshal(raw, &doc) != nil {
+			return "?"
+		}
+		return shapeOf(doc)
+	}
+	return "?"
+}
@@ -0,0 +1,81 @@
+package easymongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// spanKey is the context key the fake tracer stores its span under
+type spanKey struct{}
+
+// fakeSpan records what easymongo does with a span
+type fakeSpan struct {
+	name       string
+	parent     *fakeSpan
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *fakeSpan) SetAttributes(attributes ...easymongo.Attribute) {
+	for _, attr := range attributes {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+func (s *fakeSpan) RecordError(err error) { s.err = err }
+func (s *fakeSpan) End()                  { s.ended = true }
+
+// fakeTracer records every span it starts
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) StartSpan(ctx context.Context, name string, attributes ...easymongo.Attribute) (context.Context, easymongo.Span) {
+	parent, _ := ctx.Value(spanKey{}).(*fakeSpan)
+	span := &fakeSpan{name: name, parent: parent, attributes: map[string]interface{}{}}
+	span.SetAttributes(attributes...)
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestTracer(t *testing.T) {
+	is := assert.New(t)
+	tracer := &fakeTracer{}
+	// Nothing is listening on this port, so every operation fails
+	tmpConn, err := easymongo.ConnectWith("mongodb://127.0.0.1:1").
+		SkipGlobal().
+		Tracer(tracer).
+		Connect()
+	is.NoError(err)
+	defer tmpConn.Close(context.Background())
+
+	root := &fakeSpan{name: "request"}
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), spanKey{}, root), 50*time.Millisecond)
+	defer cancel()
+	var enemies []enemy
+	// Map keys are rendered in sorted order, so the shape of the same filter is stable
+	filter := bson.M{"timesFought": bson.M{"$in": []int{1, 2, 3}}, "name": "The Joker", "evilness": bson.M{"$gt": 90, "$lt": 100}, "deceased": false}
+	is.Error(tmpConn.Database("batman_archive").C("enemies").Find(filter).WithContext(ctx).All(&enemies))
+
+	is.Len(tracer.spans, 1)
+	if len(tracer.spans) != 1 {
+		return
+	}
+	span := tracer.spans[0]
+	is.Equal("find batman_archive.enemies", span.name)
+	is.Equal(root, span.parent, "The span should be a child of the span in the supplied context")
+	is.True(span.ended)
+	is.Error(span.err)
+	is.Equal("mongodb", span.attributes[easymongo.AttributeDBSystem])
+	is.Equal("batman_archive", span.attributes[easymongo.AttributeDBName])
+	is.Equal("enemies", span.attributes[easymongo.AttributeDBCollection])
+	is.Equal("find", span.attributes[easymongo.AttributeDBOperation])
+	is.Equal("FindQuery.All", span.attributes[easymongo.AttributeOperation])
+	is.Equal(`{"deceased":"?","evilness":{"$gt":"?","$lt":"?"},"name":"?","timesFought":{"$in":["?"]}}`, span.attributes[easymongo.AttributeDBStatement],
+		"The filter shape should not contain any values")
+}
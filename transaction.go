@@ -0,0 +1,112 @@
+package easymongo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Tx represents a multi-document transaction that is in progress.
+// Databases and Collections obtained from a Tx are bound to the transaction, so any
+// Find/Insert/Update/Delete/Aggregate queries built from them run inside of it.
+type Tx struct {
+	connection *Connection
+	// client is the client the session was started on. It is pinned for the life of the transaction,
+	// so a client swapped in by a credential rotation is not handed the session mid-transaction.
+	client     *mongo.Client
+	sessionCtx mongo.SessionContext
+	// finished is set (to 1) once the transaction has committed or aborted
+	finished int32
+}
+
+// WithTransaction starts a session and runs fn inside of a multi-document transaction.
+// If fn returns nil, the transaction is committed. If fn returns an error, the transaction
+// is aborted and the error is returned.
+// Should the server report a TransientTransactionError, the whole transaction is retried. Should it
+// report an UnknownTransactionCommitResult, the commit is retried. As a result, fn may be called more than
+// once and should not have side-effects outside of the database.
+//
+//	err = conn.WithTransaction(ctx, func(tx *easymongo.Tx) error {
+//	    if _, err := tx.D("shop").C("orders").Insert().One(order); err != nil {
+//	        return err
+//	    }
+//	    return tx.D("shop").C("ledger").Update(filter, update).One()
+//	})
+//
+// Transactions require a replica set or a sharded cluster.
+func (conn *Connection) WithTransaction(ctx context.Context, fn func(tx *Tx) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := conn.beginOperation(); err != nil {
+		return err
+	}
+	defer conn.endOperation()
+	client, release := conn.pinClient()
+	defer release()
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	var tx *Tx
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if tx != nil {
+			// The transaction is being retried - the previous attempt is over
+			tx.finish()
+		}
+		tx = &Tx{
+			connection: conn,
+			client:     client,
+			sessionCtx: sessCtx,
+		}
+		return nil, fn(tx)
+	})
+	if tx != nil {
+		tx.finish()
+	}
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		err = ErrTimeoutOccurred
+	}
+	return err
+}
+
+// finish marks the transaction as committed or aborted.
+func (tx *Tx) finish() {
+	atomic.StoreInt32(&tx.finished, 1)
+}
+
+// running returns true if tx is a transaction on conn which has not yet committed or aborted.
+func (tx *Tx) running(conn *Connection) bool {
+	return tx != nil && tx.connection == conn && atomic.LoadInt32(&tx.finished) == 0
+}
+
+// Context returns the session context the transaction runs under. This is useful when interacting
+// with mongo-go-driver objects directly (e.g. collection.MongoDriverCollection()) inside of a transaction.
+func (tx *Tx) Context() context.Context {
+	return tx.sessionCtx
+}
+
+// Connection returns the connection the transaction was started on.
+func (tx *Tx) Connection() *Connection {
+	return tx.connection
+}
+
+// Database returns a database object bound to the transaction.
+func (tx *Tx) Database(dbName string) *Database {
+	return tx.connection.Database(dbName).With(tx)
+}
+
+// D is a shorthand for returning a Database object bound to the transaction.
+// It wraps Database() and provides identical functionality.
+func (tx *Tx) D(dbName string) *Database {
+	return tx.Database(dbName)
+}
+
+// Collection returns a collection object bound to the transaction.
+func (tx *Tx) Collection(dbName, collectionName string) *Collection {
+	return tx.Database(dbName).C(collectionName)
+}
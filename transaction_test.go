@@ -0,0 +1,115 @@
+package easymongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// skipIfStandalone skips a test when the server does not support transactions
+func skipIfStandalone(t *testing.T, err error) {
+	t.Helper()
+	var cmdErr mongo.CommandError
+	// IllegalOperation - "Transaction numbers are only allowed on a replica set member or mongos"
+	if errors.As(err, &cmdErr) && cmdErr.Code == 20 {
+		t.Skipf("Transactions are not supported by the test instance: %v", err)
+	}
+}
+
+func TestWithTransaction(t *testing.T) {
+	setup(t)
+	coll := createBatmanArchive(t)
+	dbName := coll.GetDatabase().Name()
+	collName := coll.Name()
+	t.Run("Commit", func(t *testing.T) {
+		is := assert.New(t)
+		err := conn.WithTransaction(context.Background(), func(tx *easymongo.Tx) error {
+			txColl := tx.Collection(dbName, collName)
+			if _, err := txColl.Insert().One(enemy{ID: primitive.NewObjectID(), Name: "Bane"}); err != nil {
+				return err
+			}
+			return txColl.Update(bson.M{"name": "The Joker"}, bson.M{"$inc": bson.M{"timesFought": 1}}).One()
+		})
+		skipIfStandalone(t, err)
+		is.NoError(err, "Could not commit the transaction")
+		var e enemy
+		is.NoError(coll.Find(bson.M{"name": "Bane"}).One(&e), "The inserted document should exist after commit")
+	})
+	t.Run("Abort", func(t *testing.T) {
+		is := assert.New(t)
+		errAbort := errors.New("abort")
+		err := conn.WithTransaction(context.Background(), func(tx *easymongo.Tx) error {
+			if _, err := coll.With(tx).Insert().One(enemy{ID: primitive.NewObjectID(), Name: "Scarecrow"}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		skipIfStandalone(t, err)
+		is.True(errors.Is(err, errAbort), "The error from the callback should be returned")
+		var e enemy
+		err = coll.Find(bson.M{"name": "Scarecrow"}).One(&e)
+		is.True(errors.Is(err, mongo.ErrNoDocuments), "The inserted document should not exist after an abort")
+	})
+	t.Run("CredentialRotation", func(t *testing.T) {
+		is := assert.New(t)
+		rotated, err := mongo.Connect(context.Background(), options.Client().ApplyURI(conn.MongoURI()))
+		is.NoError(err, "Could not connect the rotated client")
+		var pinned *mongo.Client
+		err = conn.WithTransaction(context.Background(), func(tx *easymongo.Tx) error {
+			txColl := tx.Collection(dbName, collName)
+			if _, err := txColl.Insert().One(enemy{ID: primitive.NewObjectID(), Name: "Mr. Freeze"}); err != nil {
+				return err
+			}
+			pinned = conn.RotateClient(rotated)
+			// The previous client must not be disconnected until the transaction has finished
+			time.Sleep(100 * time.Millisecond)
+			is.Equal(pinned, txColl.MongoDriverCollection().Database().Client(),
+				"The transaction should keep using the client its session was started on")
+			return txColl.Update(bson.M{"name": "Mr. Freeze"}, bson.M{"$inc": bson.M{"timesFought": 1}}).One()
+		})
+		skipIfStandalone(t, err)
+		is.NoError(err, "The transaction should commit across a credential rotation")
+		is.Equal(rotated, conn.MongoDriverClient(), "The connection should use the rotated client")
+		var e enemy
+		is.NoError(coll.Find(bson.M{"name": "Mr. Freeze"}).One(&e), "The inserted document should exist after commit")
+		is.Equal(1, e.TimesFought)
+	})
+	t.Run("CloseMidTransaction", func(t *testing.T) {
+		is := assert.New(t)
+		tmpConn, err := easymongo.ConnectWith(conn.MongoURI()).SkipGlobal().Connect()
+		is.NoError(err)
+		closed := make(chan error, 1)
+		err = tmpConn.WithTransaction(context.Background(), func(tx *easymongo.Tx) error {
+			txColl := tx.Collection(dbName, collName)
+			if _, err := txColl.Insert().One(enemy{ID: primitive.NewObjectID(), Name: "Killer Croc"}); err != nil {
+				return err
+			}
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				closed <- tmpConn.Close(ctx)
+			}()
+			for !tmpConn.IsClosed() {
+				time.Sleep(time.Millisecond)
+			}
+			var e enemy
+			err := tmpConn.Database(dbName).C(collName).Find(bson.M{}).One(&e)
+			is.True(errors.Is(err, easymongo.ErrConnectionClosed), "New operations should be refused while closing")
+			return txColl.Update(bson.M{"name": "Killer Croc"}, bson.M{"$inc": bson.M{"timesFought": 1}}).One()
+		})
+		skipIfStandalone(t, err)
+		is.NoError(err, "A transaction running when Close is called should still commit")
+		is.NoError(<-closed, "Close should wait for the transaction to finish")
+		var e enemy
+		is.NoError(coll.Find(bson.M{"name": "Killer Croc"}).One(&e), "The inserted document should exist after commit")
+		is.Equal(1, e.TimesFought)
+	})
+}
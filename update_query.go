@@ -1,8 +1,13 @@
 package easymongo
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // UpdateQuery helps construct and execute update queries
@@ -46,6 +51,58 @@ func (uq *UpdateQuery) BypassDocumentValidation() *UpdateQuery {
 	return uq
 }
 
+// WithContext will consume the supplied context. A note that this is completely optional
+// as Timeout will auto-create a context for you if one is not supplied.
+// This is typically useful when using a context across many functions (e.g. to propagate a trace).
+func (uq *UpdateQuery) WithContext(ctx context.Context) *UpdateQuery {
+	uq.Query.setContext(&ctx)
+	return uq
+}
+
+// Retry overrides the connection's RetryPolicy for this query. Use RetryPolicy{MaxAttempts: 1} to disable retrying.
+func (uq *UpdateQuery) Retry(policy RetryPolicy) *UpdateQuery {
+	uq.Query.setRetry(policy)
+	return uq
+}
+
+// ReadPreference overrides the read preference for this query only.
+// e.g. ReadPreference(readpref.SecondaryPreferred())
+func (uq *UpdateQuery) ReadPreference(rp *readpref.ReadPref) *UpdateQuery {
+	uq.Query.setReadPreference(rp)
+	return uq
+}
+
+// ReadConcern overrides the read concern for this query only.
+func (uq *UpdateQuery) ReadConcern(rc *readconcern.ReadConcern) *UpdateQuery {
+	uq.Query.setReadConcern(rc)
+	return uq
+}
+
+// WriteConcern overrides the write concern for this query only.
+func (uq *UpdateQuery) WriteConcern(wc *writeconcern.WriteConcern) *UpdateQuery {
+	uq.Query.setWriteConcern(wc)
+	return uq
+}
+
+// Flags overrides the read concern, read preference and write concern set in flags for this query only.
+// e.g. Flags(ReadPreferenceSecondaryPreferred | ReadConcernMajority)
+func (uq *UpdateQuery) Flags(flags ConnectionFlag) *UpdateQuery {
+	uq.Query.setFlags(flags)
+	return uq
+}
+
+// ErrorIfNotFound returns ErrNoDocuments should the filter match no documents, regardless of StrictNotFound.
+func (uq *UpdateQuery) ErrorIfNotFound() *UpdateQuery {
+	uq.Query.setErrorIfNotFound(true)
+	return uq
+}
+
+// IgnoreNotFound does not return an error should the filter match no documents, regardless of StrictNotFound.
+func (uq *UpdateQuery) IgnoreNotFound() *UpdateQuery {
+	uq.Query.setErrorIfNotFound(false)
+	return uq
+}
+
 // updateOptions returns the native mongo driver options.UpdateOptions using
 // the provided query information.
 func (uq *UpdateQuery) updateOptions() *options.UpdateOptions {
@@ -62,36 +119,38 @@ func (uq *UpdateQuery) updateOptions() *options.UpdateOptions {
 }
 
 // One runs the UpdateQuery against the first matching document.
+// ErrNoDocuments is returned if nothing matched (or was upserted) when StrictNotFound or ErrorIfNotFound is set.
 // No actions are taken until this function is called.
 func (uq *UpdateQuery) One() (err error) {
 	var result *mongo.UpdateResult
-	mongoColl := uq.collection.mongoColl
-	ctx, cancelFunc := uq.getContext()
-	defer cancelFunc()
 	opts := uq.updateOptions()
-	result, err = mongoColl.UpdateOne(ctx, uq.filter, uq.updateQuery, opts)
-	// TODO: Handle ErrNotFound
-	if err == nil && result.MatchedCount == 0 {
-		// TODO: Inject ErrNotFound
-	}
-	err = uq.collection.handleErr(err)
-
-	return err
+	err = uq.run(opUpdateOne, func(ctx context.Context) (err error) {
+		result, err = uq.collection.mongoCollection().UpdateOne(ctx, uq.filter, uq.updateQuery, opts)
+		if err != nil {
+			return err
+		}
+		return uq.notFoundErr(result.MatchedCount + result.UpsertedCount)
+	})
+	return uq.collection.handleErr(err)
 }
 
 // All runs the UpdateQuery against all matching documents.
 // A note that the updatedCount includes the count for upserted documents.
+// ErrNoDocuments is returned if nothing matched (or was upserted) when StrictNotFound or ErrorIfNotFound is set.
 // No actions are taken until this function is called.
 func (uq *UpdateQuery) All() (matchedCount, updatedCount int, err error) {
 	var result *mongo.UpdateResult
-	mongoColl := uq.collection.mongoColl
-	ctx, cancelFunc := uq.getContext()
-	defer cancelFunc()
 	opts := uq.updateOptions()
-	result, err = mongoColl.UpdateMany(ctx, uq.filter, uq.updateQuery, opts)
+	err = uq.run(opUpdateAll, func(ctx context.Context) (err error) {
+		result, err = uq.collection.mongoCollection().UpdateMany(ctx, uq.filter, uq.updateQuery, opts)
+		if err != nil {
+			return err
+		}
+		return uq.notFoundErr(result.MatchedCount + result.UpsertedCount)
+	})
 	err = uq.collection.handleErr(err)
-	if err == nil && result.MatchedCount == 0 {
-		// TODO: Inject ErrNotFound
+	if err != nil {
+		return matchedCount, updatedCount, err
 	}
 	matchedCount = int(result.MatchedCount)
 	updatedCount = int(result.ModifiedCount) + int(result.UpsertedCount)
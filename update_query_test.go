@@ -1,11 +1,16 @@
 package easymongo_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tophergopher/easymongo"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func TestUpdate(t *testing.T) {
@@ -53,4 +58,29 @@ func TestUpdate(t *testing.T) {
 		is.NoError(err, "Unable to find an object to update by ID")
 		is.Len(enemies, updatedCount)
 	})
+	t.Run("Not found", func(t *testing.T) {
+		is := assert.New(t)
+		missing := bson.M{"name": "Mr. Nobody"}
+		update := bson.M{"$set": bson.M{"notes": "Who?"}}
+		is.NoError(coll.Update(missing, update).One(), "Matching nothing should not be an error by default")
+		is.NoError(coll.Delete(missing).One(), "Matching nothing should not be an error by default")
+
+		err := coll.Update(missing, update).ErrorIfNotFound().One()
+		is.True(errors.Is(err, easymongo.ErrNoDocuments), "ErrorIfNotFound should return ErrNoDocuments")
+		_, _, err = coll.Update(missing, update).ErrorIfNotFound().All()
+		is.True(errors.Is(err, easymongo.ErrNoDocuments))
+		is.True(errors.Is(coll.Replace(missing, enemy{Name: "Mr. Nobody"}).ErrorIfNotFound().One(), easymongo.ErrNoDocuments))
+		_, err = coll.Delete(missing).ErrorIfNotFound().Many()
+		is.True(errors.Is(err, easymongo.ErrNoDocuments))
+
+		strictConn, err := easymongo.ConnectWith(conn.MongoURI()).SkipGlobal().StrictNotFound().Connect()
+		is.NoError(err)
+		defer strictConn.Close(context.Background())
+		strictColl := strictConn.Database(coll.MongoDriverCollection().Database().Name()).C(coll.Name())
+		is.True(errors.Is(strictColl.UpdateByID(primitive.NewObjectID(), update), mongo.ErrNoDocuments), "StrictNotFound should return ErrNoDocuments")
+		is.True(errors.Is(strictColl.DeleteByID(primitive.NewObjectID()), easymongo.ErrNoDocuments))
+		is.NoError(strictColl.Delete(missing).IgnoreNotFound().One(), "IgnoreNotFound should override StrictNotFound")
+		is.NoError(strictColl.Update(bson.M{"name": "The Joker"}, update).One(), "Matched documents should not return an error")
+		is.NoError(strictColl.Upsert(missing, update).One(), "Upserted documents should not return an error")
+	})
 }